@@ -0,0 +1,182 @@
+package conv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// IntToBytes encodes v into a slice of unsafe.Sizeof(v) bytes in the given order
+func IntToBytes[T Integer](v T, order binary.ByteOrder) []byte {
+	return AppendInt(nil, v, order)
+}
+
+// AppendInt appends the fixed-size encoding of v to b and returns the extended slice
+func AppendInt[T Integer](b []byte, v T, order binary.ByteOrder) []byte {
+	var buf [8]byte
+	u := uint64(v)
+	switch size := unsafe.Sizeof(v); size {
+	case 1:
+		buf[0] = byte(u)
+	case 2:
+		order.PutUint16(buf[:], uint16(u))
+	case 4:
+		order.PutUint32(buf[:], uint32(u))
+	default:
+		order.PutUint64(buf[:], u)
+	}
+	return append(b, buf[:unsafe.Sizeof(v)]...)
+}
+
+// BytesToInt decodes b which must be exactly unsafe.Sizeof(T) bytes long
+func BytesToInt[T Integer](b []byte, order binary.ByteOrder) (T, error) {
+	var v T
+	size := int(unsafe.Sizeof(v))
+	if len(b) != size {
+		return 0, fmt.Errorf("cannot decode %d bytes into %T of %d bytes", len(b), v, size)
+	}
+
+	switch size {
+	case 1:
+		v = T(b[0])
+	case 2:
+		v = T(order.Uint16(b))
+	case 4:
+		v = T(order.Uint32(b))
+	default:
+		v = T(order.Uint64(b))
+	}
+	return v, nil
+}
+
+// ZigZagEncode maps signed integers to unsigned integers so that numbers with small absolute values
+// have small encodings: 0 => 0, -1 => 1, 1 => 2, -2 => 3, ...
+func ZigZagEncode[T Signed](v T) uint64 {
+	i := int64(v)
+	return uint64(i<<1) ^ uint64(i>>63)
+}
+
+// ZigZagDecode reverses ZigZagEncode
+func ZigZagDecode[T Signed](u uint64) T {
+	return T(int64(u>>1) ^ -int64(u&1))
+}
+
+// AppendUvarint appends the varint encoding of v to b
+func AppendUvarint[T Unsigned](b []byte, v T) []byte {
+	return binary.AppendUvarint(b, uint64(v))
+}
+
+// AppendVarint appends the zigzag varint encoding of v to b
+func AppendVarint[T Signed](b []byte, v T) []byte {
+	return binary.AppendUvarint(b, ZigZagEncode(v))
+}
+
+// ReadUvarint decodes a varint from the beginning of b and returns the value and the number of bytes read
+func ReadUvarint[T Unsigned](b []byte) (T, int, error) {
+	u, n := binary.Uvarint(b)
+	if n == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if n < 0 || uint64(T(u)) != u {
+		return 0, 0, strconv.ErrRange
+	}
+	return T(u), n, nil
+}
+
+// ReadVarint decodes a zigzag varint from the beginning of b and returns the value and the number of bytes read
+func ReadVarint[T Signed](b []byte) (T, int, error) {
+	u, n := binary.Uvarint(b)
+	if n == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if n < 0 {
+		return 0, 0, strconv.ErrRange
+	}
+	i := ZigZagDecode[int64](u)
+	if int64(T(i)) != i {
+		return 0, 0, strconv.ErrRange
+	}
+	return T(i), n, nil
+}
+
+// SortableBytes encodes v in big endian so that comparing encodings bytewise agrees with comparing numbers.
+// The sign bit of signed integers is flipped, and floats are encoded as their IEEE 754 bits with the sign bit
+// flipped for positive numbers and all bits flipped for negative numbers.
+func SortableBytes[T Number](v T) []byte {
+	return AppendSortable(nil, v)
+}
+
+// AppendSortable appends the sortable encoding of v to b
+func AppendSortable[T Number](b []byte, v T) []byte {
+	var u uint64
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32:
+		bits := math.Float32bits(float32(rv.Float()))
+		if bits&(1<<31) != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 31
+		}
+		u = uint64(bits)
+	case reflect.Float64:
+		u = math.Float64bits(rv.Float())
+		if u&(1<<63) != 0 {
+			u = ^u
+		} else {
+			u |= 1 << 63
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u = uint64(rv.Int()) ^ 1<<(8*rv.Type().Size()-1)
+	default:
+		u = rv.Uint()
+	}
+
+	var buf [8]byte
+	size := int(rv.Type().Size())
+	binary.BigEndian.PutUint64(buf[:], u)
+	return append(b, buf[8-size:]...)
+}
+
+// FromSortableBytes decodes the value encoded by SortableBytes
+func FromSortableBytes[T Number](b []byte) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	size := int(rv.Type().Size())
+	if len(b) != size {
+		return 0, fmt.Errorf("cannot decode %d bytes into %T of %d bytes", len(b), v, size)
+	}
+
+	var buf [8]byte
+	copy(buf[8-size:], b)
+	u := binary.BigEndian.Uint64(buf[:])
+	switch rv.Kind() {
+	case reflect.Float32:
+		bits := uint32(u)
+		if bits&(1<<31) != 0 {
+			bits &^= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		rv.SetFloat(float64(math.Float32frombits(bits)))
+	case reflect.Float64:
+		if u&(1<<63) != 0 {
+			u &^= 1 << 63
+		} else {
+			u = ^u
+		}
+		rv.SetFloat(math.Float64frombits(u))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u ^= 1 << (8*size - 1)
+		// sign-extend from size bytes
+		shift := 64 - 8*size
+		rv.SetInt(int64(u<<shift) >> shift)
+	default:
+		rv.SetUint(u)
+	}
+	return v, nil
+}
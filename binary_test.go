@@ -0,0 +1,117 @@
+package conv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestIntToBytes(t *testing.T) {
+	if diff := diffSlice([]byte{0x01, 0x02}, IntToBytes(int16(0x0102), binary.BigEndian)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]byte{0x04, 0x03, 0x02, 0x01}, IntToBytes(uint32(0x01020304), binary.LittleEndian)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]byte{0xff}, IntToBytes(int8(-1), binary.BigEndian)); diff != "" {
+		t.Fatal(diff)
+	}
+
+	type ID int64
+	b := IntToBytes(ID(-42), binary.BigEndian)
+	id, err := BytesToInt[ID](b, binary.BigEndian)
+	if err != nil || id != -42 {
+		t.Fatal(id, err)
+	}
+
+	if _, err = BytesToInt[int32](b, binary.BigEndian); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestVarint(t *testing.T) {
+	t.Run("ZigZag", func(t *testing.T) {
+		for v, u := range map[int64]uint64{0: 0, -1: 1, 1: 2, -2: 3, math.MaxInt64: math.MaxUint64 - 1, math.MinInt64: math.MaxUint64} {
+			if got := ZigZagEncode(v); got != u {
+				t.Errorf("encode %d: expect %d, got %d", v, u, got)
+			}
+			if got := ZigZagDecode[int64](u); got != v {
+				t.Errorf("decode %d: expect %d, got %d", u, v, got)
+			}
+		}
+	})
+
+	t.Run("Uvarint", func(t *testing.T) {
+		b := AppendUvarint(nil, uint32(300))
+		b = AppendUvarint(b, uint8(1))
+		v, n, err := ReadUvarint[uint32](b)
+		if err != nil || v != 300 || n != 2 {
+			t.Fatal(v, n, err)
+		}
+		v8, n, err := ReadUvarint[uint8](b[n:])
+		if err != nil || v8 != 1 || n != 1 {
+			t.Fatal(v8, n, err)
+		}
+		if _, _, err = ReadUvarint[uint8](b); !errors.Is(err, strconv.ErrRange) {
+			t.Fatal(err)
+		}
+		if _, _, err = ReadUvarint[uint32](b[:1]); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Varint", func(t *testing.T) {
+		b := AppendVarint(nil, int16(-300))
+		v, n, err := ReadVarint[int16](b)
+		if err != nil || v != -300 || n != len(b) {
+			t.Fatal(v, n, err)
+		}
+		if _, _, err = ReadVarint[int8](b); !errors.Is(err, strconv.ErrRange) {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestSortableBytes(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		values := []int32{math.MinInt32, -100, -1, 0, 1, 100, math.MaxInt32}
+		testSortable(t, values)
+	})
+
+	t.Run("Uint", func(t *testing.T) {
+		values := []uint16{0, 1, 255, 256, math.MaxUint16}
+		testSortable(t, values)
+	})
+
+	t.Run("Float64", func(t *testing.T) {
+		values := []float64{math.Inf(-1), -math.MaxFloat64, -1.5, -math.SmallestNonzeroFloat64, 0, math.SmallestNonzeroFloat64, 1, 1.5, math.MaxFloat64, math.Inf(1)}
+		testSortable(t, values)
+	})
+
+	t.Run("Float32", func(t *testing.T) {
+		values := []float32{-math.MaxFloat32, -2, -0.5, 0, 0.5, 2, math.MaxFloat32}
+		testSortable(t, values)
+	})
+}
+
+func testSortable[T Number](t *testing.T, values []T) {
+	encoded := make([][]byte, len(values))
+	for i, v := range values {
+		encoded[i] = SortableBytes(v)
+		got, err := FromSortableBytes[T](encoded[i])
+		if err != nil || got != v {
+			t.Fatalf("expect %v, got %v %v", v, got, err)
+		}
+	}
+
+	if !sort.SliceIsSorted(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	}) {
+		t.Fatalf("encodings of %v are not sorted", values)
+	}
+}
@@ -1,10 +1,6 @@
 package conv
 
 import (
-	"encoding"
-	"encoding/gob"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -28,130 +24,149 @@ func ToBytes(i any) ([]byte, error) {
 	return nil, fmt.Errorf("cannot convert %#v of type %T to []byte", i, i)
 }
 
-func ToByteArray8[T []byte | string](v T) [8]byte {
-	if len(v) > 8 {
-		panic("cannot convert into [8]byte")
-	}
-	var a [8]byte
-	copy(a[:], v[:])
-	return a
-}
+// PadPolicy decides how a short input fills a byte array
+type PadPolicy int
 
-func ToByteArray16[T []byte | string](v T) [16]byte {
-	if len(v) > 16 {
-		panic("cannot convert into [16]byte")
-	}
-	var a [16]byte
-	copy(a[:], v[:])
-	return a
-}
+const (
+	// PadRight copies bytes to the beginning of the array and leaves zeros at the end
+	PadRight PadPolicy = iota
+	// PadLeft copies bytes to the end of the array and leaves zeros at the beginning
+	PadLeft
+	// PadNone rejects inputs shorter than the array
+	PadNone
+)
 
-func ToByteArray32[T []byte | string](v T) [32]byte {
-	if len(v) > 32 {
-		panic("cannot convert into [32]byte")
-	}
-	var a [32]byte
-	copy(a[:], v[:])
-	return a
-}
+// TruncatePolicy decides how a long input fills a byte array
+type TruncatePolicy int
 
-func ToByteArray64[T []byte | string](v T) [64]byte {
-	if len(v) > 64 {
-		panic("cannot convert into [64]byte")
-	}
-	var a [64]byte
-	copy(a[:], v[:])
-	return a
+const (
+	// TruncateNone rejects inputs longer than the array
+	TruncateNone TruncatePolicy = iota
+	// TruncateRight keeps the leading bytes and drops the trailing ones
+	TruncateRight
+	// TruncateLeft keeps the trailing bytes and drops the leading ones
+	TruncateLeft
+)
+
+type ByteArrayOptions struct {
+	Padding    PadPolicy
+	Truncation TruncatePolicy
+
+	// Encoding is used to decode a string input
+	Encoding Encoding
 }
 
-func UnsafeMarshal(i any) ([]byte, error) {
-	if data, ok := i.([]byte); ok {
-		return data, nil
+// ToByteArray converts v to byte array A, e.g. [20]byte
+// v can be string, []byte, byte array or any types accepted by ToBytes
+func ToByteArray[A any](v any, optFns ...func(options *ByteArrayOptions)) (A, error) {
+	options := &ByteArrayOptions{}
+	for _, fn := range optFns {
+		fn(options)
 	}
 
-	var data []byte
-	srcType := reflect.TypeOf(i)
-	dstType := reflect.TypeOf(data)
-	if srcType.AssignableTo(dstType) {
-		reflect.ValueOf(&data).Elem().Set(reflect.ValueOf(i))
-		return data, nil
+	var a A
+	av := reflect.ValueOf(&a).Elem()
+	if av.Kind() != reflect.Array || av.Type().Elem().Kind() != reflect.Uint8 {
+		return a, fmt.Errorf("%T is not a byte array", a)
 	}
 
-	if srcType.ConvertibleTo(dstType) {
-		reflect.ValueOf(&data).Elem().Set(reflect.ValueOf(i).Convert(dstType))
-		return data, nil
+	v = Indirect(v)
+	var b []byte
+	var err error
+	if s, ok := v.(string); ok {
+		b, err = DecodeBytes(s, options.Encoding)
+		if err != nil {
+			return a, fmt.Errorf("decode %v: %w", options.Encoding, err)
+		}
+	} else if rv := reflect.ValueOf(v); rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b = make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+	} else if b, err = ToBytes(v); err != nil {
+		return a, err
 	}
 
-	if m, ok := i.(encoding.BinaryMarshaler); ok {
-		return m.MarshalBinary()
+	if err = fillByteArray(av, b, options); err != nil {
+		return a, err
 	}
+	return a, nil
+}
 
-	if m, ok := i.(encoding.TextMarshaler); ok {
-		return m.MarshalText()
+// fillByteArray copies b into byte array dst which must be settable
+func fillByteArray(dst reflect.Value, b []byte, options *ByteArrayOptions) error {
+	n := dst.Len()
+	if len(b) > n {
+		switch options.Truncation {
+		case TruncateRight:
+			b = b[:n]
+		case TruncateLeft:
+			b = b[len(b)-n:]
+		default:
+			return fmt.Errorf("cannot convert %d bytes into %v", len(b), dst.Type())
+		}
 	}
 
-	if m, ok := i.(json.Marshaler); ok {
-		return m.MarshalJSON()
+	if len(b) < n && options.Padding == PadNone {
+		return fmt.Errorf("cannot convert %d bytes into %v", len(b), dst.Type())
 	}
 
-	if m, ok := i.(gob.GobEncoder); ok {
-		return m.GobEncode()
+	dst.Set(reflect.Zero(dst.Type()))
+	if options.Padding == PadLeft {
+		dst = dst.Slice(n-len(b), n)
 	}
-
-	return nil, errors.New("cannot convert ")
+	reflect.Copy(dst, reflect.ValueOf(b))
+	return nil
 }
 
-func UnsafeUnmarshal(data []byte, i any) (err error) {
-	if reflect.ValueOf(i).Kind() != reflect.Pointer {
-		return fmt.Errorf("cannot unmarshal to non pointer type: %T", i)
+// ToByteArray8 converts v to [8]byte, will panic if v is longer than 8 bytes
+//
+// Deprecated: use ToByteArray which returns an error instead of panicking
+func ToByteArray8[T []byte | string](v T) [8]byte {
+	a, err := ToByteArray[[8]byte](v)
+	if err != nil {
+		panic("cannot convert into [8]byte")
 	}
+	return a
+}
 
-	if p, ok := i.(*[]byte); ok {
-		*p = data
-		return nil
+// ToByteArray16 converts v to [16]byte, will panic if v is longer than 16 bytes
+//
+// Deprecated: use ToByteArray which returns an error instead of panicking
+func ToByteArray16[T []byte | string](v T) [16]byte {
+	a, err := ToByteArray[[16]byte](v)
+	if err != nil {
+		panic("cannot convert into [16]byte")
 	}
+	return a
+}
 
-	srcType := reflect.TypeOf(data)
-	dstType := reflect.TypeOf(i).Elem()
-	if srcType.AssignableTo(dstType) {
-		reflect.ValueOf(i).Elem().Set(reflect.ValueOf(data))
-		return nil
+// ToByteArray32 converts v to [32]byte, will panic if v is longer than 32 bytes
+//
+// Deprecated: use ToByteArray which returns an error instead of panicking
+func ToByteArray32[T []byte | string](v T) [32]byte {
+	a, err := ToByteArray[[32]byte](v)
+	if err != nil {
+		panic("cannot convert into [32]byte")
 	}
+	return a
+}
 
-	if srcType.ConvertibleTo(dstType) {
-		reflect.ValueOf(i).Elem().Set(reflect.ValueOf(data).Convert(dstType))
-		return nil
+// ToByteArray64 converts v to [64]byte, will panic if v is longer than 64 bytes
+//
+// Deprecated: use ToByteArray which returns an error instead of panicking
+func ToByteArray64[T []byte | string](v T) [64]byte {
+	a, err := ToByteArray[[64]byte](v)
+	if err != nil {
+		panic("cannot convert into [64]byte")
 	}
+	return a
+}
 
-	// i is a pointer
-	// v is pointer of the same type
-	v := DeepNew(reflect.TypeOf(i).Elem())
-	defer func() {
-		if err == nil {
-			// assign v to i
-			// i is a parameter, it cannot be set, the value it points to can be set
-			// assign v.Elem to i.Elem
-			reflect.ValueOf(i).Elem().Set(v.Elem())
-		}
-	}()
-
-	for p := v; p.Kind() == reflect.Pointer && !p.IsNil(); p = p.Elem() {
-		if u, ok := p.Interface().(encoding.BinaryUnmarshaler); ok {
-			return u.UnmarshalBinary(data)
-		}
-
-		if u, ok := p.Interface().(encoding.TextUnmarshaler); ok {
-			return u.UnmarshalText(data)
-		}
-
-		if u, ok := p.Interface().(json.Unmarshaler); ok {
-			return u.UnmarshalJSON(data)
-		}
-
-		if d, ok := p.Interface().(gob.GobDecoder); ok {
-			return d.GobDecode(data)
-		}
-	}
+// UnsafeMarshal marshals i with DefaultCodecRegistry
+func UnsafeMarshal(i any) ([]byte, error) {
+	return DefaultCodecRegistry.Marshal(i)
+}
 
-	return fmt.Errorf("cannot unmarshal into: %T", i)
+// UnsafeUnmarshal unmarshals data into i with DefaultCodecRegistry
+func UnsafeUnmarshal(data []byte, i any) error {
+	return DefaultCodecRegistry.Unmarshal(data, i)
 }
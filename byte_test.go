@@ -57,3 +57,80 @@ func TestMarshalJSON(t *testing.T) {
 	}
 	t.Log(o2.ID, o2.Text)
 }
+
+func TestToByteArray(t *testing.T) {
+	t.Run("Padding", func(t *testing.T) {
+		a, err := ToByteArray[[4]byte]([]byte{1, 2})
+		if err != nil || a != [4]byte{1, 2, 0, 0} {
+			t.Fatal(a, err)
+		}
+
+		a, err = ToByteArray[[4]byte]([]byte{1, 2}, func(options *ByteArrayOptions) {
+			options.Padding = PadLeft
+		})
+		if err != nil || a != [4]byte{0, 0, 1, 2} {
+			t.Fatal(a, err)
+		}
+
+		_, err = ToByteArray[[4]byte]([]byte{1, 2}, func(options *ByteArrayOptions) {
+			options.Padding = PadNone
+		})
+		if err == nil {
+			t.Fatal("should fail")
+		}
+	})
+
+	t.Run("Truncation", func(t *testing.T) {
+		_, err := ToByteArray[[2]byte]([]byte{1, 2, 3})
+		if err == nil {
+			t.Fatal("should fail")
+		}
+
+		a, err := ToByteArray[[2]byte]([]byte{1, 2, 3}, func(options *ByteArrayOptions) {
+			options.Truncation = TruncateRight
+		})
+		if err != nil || a != [2]byte{1, 2} {
+			t.Fatal(a, err)
+		}
+
+		a, err = ToByteArray[[2]byte]([]byte{1, 2, 3}, func(options *ByteArrayOptions) {
+			options.Truncation = TruncateLeft
+		})
+		if err != nil || a != [2]byte{2, 3} {
+			t.Fatal(a, err)
+		}
+	})
+
+	t.Run("Encoding", func(t *testing.T) {
+		type Address [20]byte
+		addr, err := ToByteArray[Address]("0x00000000219ab540356cbb839cbe05303d7705fa"[2:], func(options *ByteArrayOptions) {
+			options.Encoding = EncodingHex
+		})
+		if err != nil || addr[19] != 0xfa || addr[4] != 0x21 {
+			t.Fatal(addr, err)
+		}
+
+		nonce, err := ToByteArray[[12]byte]("AAECAwQFBgcICQoL", func(options *ByteArrayOptions) {
+			options.Encoding = EncodingBase64
+		})
+		if err != nil || nonce != [12]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11} {
+			t.Fatal(nonce, err)
+		}
+	})
+
+	t.Run("Array", func(t *testing.T) {
+		a, err := ToByteArray[[4]byte](&[2]byte{1, 2})
+		if err != nil || a != [4]byte{1, 2, 0, 0} {
+			t.Fatal(a, err)
+		}
+	})
+
+	t.Run("NotByteArray", func(t *testing.T) {
+		if _, err := ToByteArray[[]byte]("abc"); err == nil {
+			t.Fatal("should fail")
+		}
+		if _, err := ToByteArray[[2]int]("ab"); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Float is a constraint that permits any floating-point type
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint that permits any integer or floating-point type
+type Number interface {
+	Integer | Float
+}
+
+var (
+	// ErrSignLoss means a negative value is cast to an unsigned type
+	ErrSignLoss = errors.New("sign loss")
+
+	// ErrFractionLoss means a value with fraction is cast to an integer type
+	ErrFractionLoss = errors.New("fraction loss")
+)
+
+// SafeCast converts v to type To
+// Returned error wraps strconv.ErrRange if v overflows To, ErrSignLoss if v is negative and To is unsigned,
+// or ErrFractionLoss if v has fraction and To is an integer type.
+// Integers are always castable to floats though large integers may be rounded.
+func SafeCast[To, From Number](v From) (To, error) {
+	var to To
+	tv := reflect.ValueOf(&to).Elem()
+	sv := reflect.ValueOf(v)
+	if err := checkCast(tv, sv); err != nil {
+		return 0, fmt.Errorf("cannot cast %v of type %T to %T: %w", v, v, to, err)
+	}
+	return To(v), nil
+}
+
+func checkCast(dst, src reflect.Value) error {
+	switch {
+	case IsIntValue(src):
+		i := src.Int()
+		switch {
+		case IsIntValue(dst):
+			if dst.OverflowInt(i) {
+				return strconv.ErrRange
+			}
+		case isCastUintValue(dst):
+			if i < 0 {
+				return ErrSignLoss
+			}
+			if dst.OverflowUint(uint64(i)) {
+				return strconv.ErrRange
+			}
+		}
+	case isCastUintValue(src):
+		u := src.Uint()
+		switch {
+		case IsIntValue(dst):
+			if u > math.MaxInt64 || dst.OverflowInt(int64(u)) {
+				return strconv.ErrRange
+			}
+		case isCastUintValue(dst):
+			if dst.OverflowUint(u) {
+				return strconv.ErrRange
+			}
+		}
+	case IsFloatValue(src):
+		f := src.Float()
+		switch {
+		case IsFloatValue(dst):
+			if dst.OverflowFloat(f) {
+				return strconv.ErrRange
+			}
+		case math.IsNaN(f) || math.IsInf(f, 0):
+			return strconv.ErrRange
+		case f != math.Trunc(f):
+			return ErrFractionLoss
+		case IsIntValue(dst):
+			// -2^63 is exact in float64 while 2^63 is out of int64 range
+			if f < math.MinInt64 || f >= -math.MinInt64 || dst.OverflowInt(int64(f)) {
+				return strconv.ErrRange
+			}
+		case isCastUintValue(dst):
+			if f < 0 {
+				return ErrSignLoss
+			}
+			if f >= math.MaxUint64 || dst.OverflowUint(uint64(f)) {
+				return strconv.ErrRange
+			}
+		}
+	}
+	return nil
+}
+
+// isCastUintValue is IsUintValue including uintptr which is permitted by Unsigned
+func isCastUintValue(v reflect.Value) bool {
+	return IsUintValue(v) || v.Kind() == reflect.Uintptr
+}
+
+// SafeCastSlice converts elements of a to type To
+func SafeCastSlice[To, From Number](a []From) ([]To, error) {
+	if a == nil {
+		return nil, nil
+	}
+	res := make([]To, len(a))
+	var err error
+	for i, v := range a {
+		res[i], err = SafeCast[To](v)
+		if err != nil {
+			return nil, fmt.Errorf("convert index %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// SafeCastP converts the value p points to into type To
+// nil is returned if p is nil
+func SafeCastP[To, From Number](p *From) (*To, error) {
+	if p == nil {
+		return nil, nil
+	}
+	v, err := SafeCast[To](*p)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// MustCast panics if SafeCast(v) failed
+func MustCast[To, From Number](v From) To {
+	res, err := SafeCast[To](v)
+	if err != nil {
+		log.Panic(err)
+	}
+	return res
+}
+
+// MustCastSlice panics if SafeCastSlice(a) failed
+func MustCastSlice[To, From Number](a []From) []To {
+	res, err := SafeCastSlice[To](a)
+	if err != nil {
+		log.Panic(err)
+	}
+	return res
+}
+
+// MustCastP panics if SafeCastP(p) failed
+func MustCastP[To, From Number](p *From) *To {
+	res, err := SafeCastP[To](p)
+	if err != nil {
+		log.Panic(err)
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+package conv
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestSafeCast(t *testing.T) {
+	t.Run("Good", func(t *testing.T) {
+		if v, err := SafeCast[int8](int64(-128)); err != nil || v != -128 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[uint8](255); err != nil || v != 255 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[int64](uint64(math.MaxInt64)); err != nil || v != math.MaxInt64 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[int32](float64(-1 << 31)); err != nil || v != math.MinInt32 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[int64](float64(math.MinInt64)); err != nil || v != math.MinInt64 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[float32](1.5); err != nil || v != 1.5 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[float64](uint64(math.MaxUint64)); err != nil || v != math.MaxUint64 {
+			t.Fatal(v, err)
+		}
+		type ID uint16
+		if v, err := SafeCast[ID](int(42)); err != nil || v != 42 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[uintptr](int64(8)); err != nil || v != 8 {
+			t.Fatal(v, err)
+		}
+		if v, err := SafeCast[int8](uintptr(127)); err != nil || v != 127 {
+			t.Fatal(v, err)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		cases := []struct {
+			Cast func() error
+			Err  error
+		}{
+			{func() error { _, err := SafeCast[int8](128); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[int8](-129); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[uint8](-1); return err }, ErrSignLoss},
+			{func() error { _, err := SafeCast[uint64](int64(-1)); return err }, ErrSignLoss},
+			{func() error { _, err := SafeCast[int64](uint64(math.MaxInt64 + 1)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[uint16](uint32(65536)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[int](1.5); return err }, ErrFractionLoss},
+			{func() error { _, err := SafeCast[uintptr](-1); return err }, ErrSignLoss},
+			{func() error { _, err := SafeCast[uintptr](-1.0); return err }, ErrSignLoss},
+			{func() error { _, err := SafeCast[int8](uintptr(300)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[uint8](uintptr(256)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[uint](-1.0); return err }, ErrSignLoss},
+			{func() error { _, err := SafeCast[int64](math.Pow(2, 63)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[uint64](math.Pow(2, 64)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[int](math.NaN()); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[int](math.Inf(1)); return err }, strconv.ErrRange},
+			{func() error { _, err := SafeCast[float32](math.MaxFloat64); return err }, strconv.ErrRange},
+		}
+		for i, c := range cases {
+			if err := c.Cast(); !errors.Is(err, c.Err) {
+				t.Errorf("case %d: expect %v, got %v", i, c.Err, err)
+			}
+		}
+	})
+}
+
+func TestSafeCastSlice(t *testing.T) {
+	l, err := SafeCastSlice[uint8]([]int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]uint8{1, 2, 3}, l); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if _, err = SafeCastSlice[uint8]([]int{1, 256}); !errors.Is(err, strconv.ErrRange) {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeCastP(t *testing.T) {
+	p, err := SafeCastP[int16, float64](nil)
+	if err != nil || p != nil {
+		t.Fatal(p, err)
+	}
+
+	f := 12.0
+	p, err = SafeCastP[int16](&f)
+	if err != nil || *p != 12 {
+		t.Fatal(p, err)
+	}
+}
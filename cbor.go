@@ -0,0 +1,686 @@
+package conv
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// CBORTimeFormat decides how time.Time is encoded in CBOR
+type CBORTimeFormat int
+
+const (
+	// CBORTimeEpoch encodes time as tag 1 with epoch seconds, integer if there is no fraction
+	CBORTimeEpoch CBORTimeFormat = iota
+	// CBORTimeString encodes time as tag 0 with RFC 3339 string
+	CBORTimeString
+)
+
+type CBOROptions struct {
+	// Canonical enables deterministic encoding defined in RFC 8949 section 4.2.1:
+	// floats are encoded in the shortest form preserving the value, and map keys are sorted
+	// by the bytewise lexicographic order of their encodings.
+	// Integers and lengths are always encoded in the shortest form.
+	Canonical bool
+
+	TimeFormat CBORTimeFormat
+
+	// FieldNameMatcher matches map keys and struct field names while decoding, cbor tag names take precedence
+	FieldNameMatcher FieldNameMatcher
+}
+
+// CBORTag is a tagged data item whose tag number is not interpreted by the decoder
+type CBORTag struct {
+	Number  uint64
+	Content any
+}
+
+// CBORSimple is a simple value other than false, true, null and undefined
+type CBORSimple uint8
+
+// CBORCodec marshals values with MarshalCBOR and unmarshals with UnmarshalCBOR
+var CBORCodec Codec = cborCodec{}
+
+var (
+	bigIntType     = reflect.TypeOf(big.Int{})
+	cborTagType    = reflect.TypeOf(CBORTag{})
+	cborSimpleType = reflect.TypeOf(CBORSimple(0))
+)
+
+// MarshalCBOR encodes v in CBOR defined in RFC 8949
+// Struct fields are encoded as maps keyed by cbor tag names or field names, embedded structs are flattened.
+// time.Time is encoded with tag 0 or 1, big.Int out of 64-bit range with tag 2 or 3,
+// encoding.TextMarshaler as text string.
+func MarshalCBOR(v any, optFns ...func(options *CBOROptions)) ([]byte, error) {
+	options := &CBOROptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	return appendCBOR(nil, reflect.ValueOf(v), options)
+}
+
+// UnmarshalCBOR decodes CBOR data b into dst which must be a non-nil pointer
+// Data is decoded into generic values first, then assigned to dst in the same way as UnsafeAssign:
+// maps are decoded as map[string]any, arrays as []any, integers as int64, uint64, or *big.Int if out of range,
+// floats as float64, tag 0 and 1 as time.Time, tag 2 and 3 as *big.Int and other tags as CBORTag.
+func UnmarshalCBOR(b []byte, dst any, optFns ...func(options *CBOROptions)) error {
+	options := &CBOROptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("cannot decode into %T", dst)
+	}
+
+	d := &cborDecoder{data: b}
+	v, err := d.decode()
+	if err != nil {
+		return fmt.Errorf("cbor: %w", err)
+	}
+	if d.pos != len(b) {
+		return fmt.Errorf("cbor: %d bytes remain after decoding", len(b)-d.pos)
+	}
+
+	assignOptions := &UnsafeAssignOptions{
+		FieldNameMatcher: options.FieldNameMatcher,
+		TagName:          "cbor",
+		Overwrite:        true,
+	}
+	if assignOptions.FieldNameMatcher == nil {
+		assignOptions.FieldNameMatcher = fieldNameEqual{}
+	}
+	if ev := dv.Elem(); ev.Kind() == reflect.Interface && (v == nil || reflect.TypeOf(v).AssignableTo(ev.Type())) {
+		// keep generic values such as *big.Int as they are
+		if v == nil {
+			ev.Set(reflect.Zero(ev.Type()))
+		} else {
+			ev.Set(reflect.ValueOf(v))
+		}
+		return nil
+	}
+	if err = unsafeAssign(dv.Elem(), reflect.ValueOf(v), assignOptions); err != nil {
+		return fmt.Errorf("cannot assign %T to %T: %w", v, dst, err)
+	}
+	return nil
+}
+
+const (
+	cborUint byte = iota << 5
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+func appendCBORHead(b []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(b, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(b, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, major|27), n)
+	}
+}
+
+func appendCBORInt(b []byte, i int64) []byte {
+	if i < 0 {
+		return appendCBORHead(b, cborNegative, uint64(-1-i))
+	}
+	return appendCBORHead(b, cborUint, uint64(i))
+}
+
+func appendCBORFloat(b []byte, f float64, bitSize int, canonical bool) []byte {
+	if canonical {
+		if math.IsNaN(f) {
+			return append(b, cborSimple|25, 0x7e, 0x00)
+		}
+		if h, ok := float64ToFloat16(f); ok {
+			return binary.BigEndian.AppendUint16(append(b, cborSimple|25), h)
+		}
+		if float64(float32(f)) == f {
+			bitSize = 32
+		} else {
+			bitSize = 64
+		}
+	}
+
+	if bitSize == 32 {
+		return binary.BigEndian.AppendUint32(append(b, cborSimple|26), math.Float32bits(float32(f)))
+	}
+	return binary.BigEndian.AppendUint64(append(b, cborSimple|27), math.Float64bits(f))
+}
+
+func appendCBOR(b []byte, v reflect.Value, options *CBOROptions) ([]byte, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(b, cborSimple|22), nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return append(b, cborSimple|22), nil
+	}
+
+	switch t := v.Type(); {
+	case t == timeType:
+		return appendCBORTime(b, v.Interface().(time.Time), options), nil
+	case t == bigIntType:
+		return appendCBORBigInt(b, addressable(v).Addr().Interface().(*big.Int)), nil
+	case t == cborSimpleType:
+		n := v.Uint()
+		if n < 24 {
+			return append(b, cborSimple|byte(n)), nil
+		}
+		return append(b, cborSimple|24, byte(n)), nil
+	case t == cborTagType:
+		tag := v.Interface().(CBORTag)
+		b = appendCBORHead(b, cborTag, tag.Number)
+		return appendCBOR(b, reflect.ValueOf(tag.Content), options)
+	case t.Implements(textMarshaler):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("marshal text: %w", err)
+		}
+		b = appendCBORHead(b, cborText, uint64(len(text)))
+		return append(b, text...), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, cborSimple|21), nil
+		}
+		return append(b, cborSimple|20), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendCBORInt(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendCBORHead(b, cborUint, v.Uint()), nil
+	case reflect.Float32:
+		return appendCBORFloat(b, v.Float(), 32, options.Canonical), nil
+	case reflect.Float64:
+		return appendCBORFloat(b, v.Float(), 64, options.Canonical), nil
+	case reflect.String:
+		b = appendCBORHead(b, cborText, uint64(v.Len()))
+		return append(b, v.String()...), nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(b, cborSimple|22), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b = appendCBORHead(b, cborBytes, uint64(v.Len()))
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			return append(b, data...), nil
+		}
+		b = appendCBORHead(b, cborArray, uint64(v.Len()))
+		var err error
+		for i := 0; i < v.Len(); i++ {
+			b, err = appendCBOR(b, v.Index(i), options)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return b, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return append(b, cborSimple|22), nil
+		}
+		entries := make([]cborEntry, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			entries = append(entries, cborEntry{key: iter.Key(), value: iter.Value()})
+		}
+		return appendCBOREntries(b, entries, options)
+	case reflect.Struct:
+		return appendCBOREntries(b, collectCBORFields(v), options)
+	default:
+		return nil, fmt.Errorf("cbor: unsupported type %v", v.Type())
+	}
+}
+
+type cborEntry struct {
+	key   reflect.Value
+	value reflect.Value
+}
+
+type cborEncodedEntry struct {
+	key   []byte
+	value []byte
+}
+
+func appendCBOREntries(b []byte, entries []cborEntry, options *CBOROptions) ([]byte, error) {
+	b = appendCBORHead(b, cborMap, uint64(len(entries)))
+	if !options.Canonical {
+		var err error
+		for _, e := range entries {
+			if b, err = appendCBOR(b, e.key, options); err != nil {
+				return nil, fmt.Errorf("key %v: %w", e.key, err)
+			}
+			if b, err = appendCBOR(b, e.value, options); err != nil {
+				return nil, fmt.Errorf("value of %v: %w", e.key, err)
+			}
+		}
+		return b, nil
+	}
+
+	encoded := make([]cborEncodedEntry, len(entries))
+	for i, e := range entries {
+		k, err := appendCBOR(nil, e.key, options)
+		if err != nil {
+			return nil, fmt.Errorf("key %v: %w", e.key, err)
+		}
+		v, err := appendCBOR(nil, e.value, options)
+		if err != nil {
+			return nil, fmt.Errorf("value of %v: %w", e.key, err)
+		}
+		encoded[i] = cborEncodedEntry{key: k, value: v}
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i].key, encoded[j].key) < 0
+	})
+	for _, e := range encoded {
+		b = append(b, e.key...)
+		b = append(b, e.value...)
+	}
+	return b, nil
+}
+
+func collectCBORFields(v reflect.Value) []cborEntry {
+	var entries []cborEntry
+	for _, f := range structFields(v.Type(), "cbor", nil) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// nil embedded pointer
+			continue
+		}
+		if hasTagOption(f.opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		entries = append(entries, cborEntry{key: reflect.ValueOf(f.name), value: fv})
+	}
+	return entries
+}
+
+func appendCBORTime(b []byte, t time.Time, options *CBOROptions) []byte {
+	if options.TimeFormat == CBORTimeString {
+		s := t.Format(time.RFC3339Nano)
+		b = appendCBORHead(b, cborTag, 0)
+		b = appendCBORHead(b, cborText, uint64(len(s)))
+		return append(b, s...)
+	}
+
+	b = appendCBORHead(b, cborTag, 1)
+	if t.Nanosecond() == 0 {
+		return appendCBORInt(b, t.Unix())
+	}
+	f := float64(t.Unix()) + float64(t.Nanosecond())/1e9
+	return appendCBORFloat(b, f, 64, options.Canonical)
+}
+
+func appendCBORBigInt(b []byte, n *big.Int) []byte {
+	if n.IsInt64() {
+		return appendCBORInt(b, n.Int64())
+	}
+	if n.IsUint64() {
+		return appendCBORHead(b, cborUint, n.Uint64())
+	}
+
+	if n.Sign() > 0 {
+		data := n.Bytes()
+		b = appendCBORHead(b, cborTag, 2)
+		b = appendCBORHead(b, cborBytes, uint64(len(data)))
+		return append(b, data...)
+	}
+
+	// -1 - n
+	m := new(big.Int).Neg(n)
+	m.Sub(m, big.NewInt(1))
+	if m.IsUint64() {
+		return appendCBORHead(b, cborNegative, m.Uint64())
+	}
+	data := m.Bytes()
+	b = appendCBORHead(b, cborTag, 3)
+	b = appendCBORHead(b, cborBytes, uint64(len(data)))
+	return append(b, data...)
+}
+
+// addressable returns an addressable copy of v
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	pv := reflect.New(v.Type())
+	pv.Elem().Set(v)
+	return pv.Elem()
+}
+
+// float64ToFloat16 returns the IEEE 754 half precision bits of f if f can be represented exactly
+func float64ToFloat16(f float64) (uint16, bool) {
+	var sign uint16
+	if math.Signbit(f) {
+		sign = 0x8000
+	}
+
+	switch {
+	case math.IsInf(f, 0):
+		return sign | 0x7c00, true
+	case f == 0:
+		return sign, true
+	}
+
+	f32 := float32(f)
+	if float64(f32) != f {
+		return 0, false
+	}
+
+	bits := math.Float32bits(f32)
+	exp := int(bits>>23&0xff) - 127
+	mant := bits & 0x7fffff
+	switch {
+	case exp >= -14 && exp <= 15:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exp+15)<<10 | uint16(mant>>13), true
+	case exp >= -24 && exp < -14:
+		// subnormal: value is k * 2^-24
+		full := mant | 0x800000
+		shift := uint(-exp - 1)
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	default:
+		return 0, false
+	}
+}
+
+func float16ToFloat64(h uint16) float64 {
+	exp := int(h >> 10 & 0x1f)
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
+
+var errCBORBreak = errors.New("unexpected break")
+
+type cborDecoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (d *cborDecoder) enter() error {
+	if d.depth >= maxDecodeDepth {
+		return fmt.Errorf("exceeded max depth %d", maxDecodeDepth)
+	}
+	d.depth++
+	return nil
+}
+
+func (d *cborDecoder) leave() {
+	d.depth--
+}
+
+func (d *cborDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errors.New("unexpected end of data")
+	}
+	c := d.data[d.pos]
+	d.pos++
+	return c, nil
+}
+
+func (d *cborDecoder) readN(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of data")
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// readHead reads the initial byte and argument of a data item
+// indefinite is true if additional information is 31
+func (d *cborDecoder) readHead() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	c, err := d.readByte()
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	major, info = c&0xe0, c&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), false, nil
+	case info <= 27:
+		b, err := d.readN(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, false, err
+		}
+		for _, x := range b {
+			arg = arg<<8 | uint64(x)
+		}
+		return major, info, arg, false, nil
+	case info == 31:
+		return major, info, 0, true, nil
+	default:
+		return 0, 0, 0, false, fmt.Errorf("invalid additional information %d", info)
+	}
+}
+
+func (d *cborDecoder) decode() (any, error) {
+	major, info, arg, indefinite, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if indefinite && (major == cborUint || major == cborNegative || major == cborTag) {
+		return nil, fmt.Errorf("invalid indefinite length for major type %d", major>>5)
+	}
+
+	if major == cborArray || major == cborMap || major == cborTag {
+		if err = d.enter(); err != nil {
+			return nil, err
+		}
+		defer d.leave()
+	}
+
+	switch major {
+	case cborUint:
+		if arg > math.MaxInt64 {
+			return arg, nil
+		}
+		return int64(arg), nil
+	case cborNegative:
+		if arg > math.MaxInt64 {
+			n := new(big.Int).SetUint64(arg)
+			return n.Neg(n).Sub(n, big.NewInt(1)), nil
+		}
+		return -1 - int64(arg), nil
+	case cborBytes:
+		return d.decodeString(major, arg, indefinite)
+	case cborText:
+		b, err := d.decodeString(major, arg, indefinite)
+		return string(b), err
+	case cborArray:
+		a := make([]any, 0, minUint64(arg, 1024))
+		for i := uint64(0); indefinite || i < arg; i++ {
+			v, err := d.decode()
+			if err == errCBORBreak && indefinite {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		return a, nil
+	case cborMap:
+		m := make(map[string]any, minUint64(arg, 1024))
+		for i := uint64(0); indefinite || i < arg; i++ {
+			k, err := d.decode()
+			if err == errCBORBreak && indefinite {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			key, err := ToString(k)
+			if err != nil {
+				return nil, fmt.Errorf("map key: %w", err)
+			}
+			if m[key], err = d.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case cborTag:
+		content, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		return decodeCBORTag(arg, content)
+	default:
+		return decodeCBORSimple(info, arg, indefinite)
+	}
+}
+
+// decodeString decodes byte or text string, chunks of indefinite length strings are concatenated
+func (d *cborDecoder) decodeString(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		b, err := d.readN(n)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{}, b...), nil
+	}
+
+	res := []byte{}
+	for {
+		m, _, arg, chunkIndefinite, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if m == cborSimple && chunkIndefinite {
+			return res, nil
+		}
+		if m != major || chunkIndefinite {
+			return nil, errors.New("invalid chunk of indefinite length string")
+		}
+		b, err := d.readN(arg)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, b...)
+	}
+}
+
+func decodeCBORTag(number uint64, content any) (any, error) {
+	switch number {
+	case 0:
+		s, ok := content.(string)
+		if !ok {
+			return nil, fmt.Errorf("tag 0: expect text string, got %T", content)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("tag 0: %w", err)
+		}
+		return t, nil
+	case 1:
+		switch v := content.(type) {
+		case int64:
+			return time.Unix(v, 0).UTC(), nil
+		case float64:
+			sec, frac := math.Modf(v)
+			return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), nil
+		default:
+			return nil, fmt.Errorf("tag 1: expect number, got %T", content)
+		}
+	case 2, 3:
+		b, ok := content.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("tag %d: expect byte string, got %T", number, content)
+		}
+		n := new(big.Int).SetBytes(b)
+		if number == 3 {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+		return n, nil
+	default:
+		return CBORTag{Number: number, Content: content}, nil
+	}
+}
+
+func decodeCBORSimple(info byte, arg uint64, indefinite bool) (any, error) {
+	switch {
+	case indefinite:
+		return nil, errCBORBreak
+	case info == 20:
+		return false, nil
+	case info == 21:
+		return true, nil
+	case info == 22, info == 23:
+		return nil, nil
+	case info == 25:
+		return float16ToFloat64(uint16(arg)), nil
+	case info == 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case info == 27:
+		return math.Float64frombits(arg), nil
+	default:
+		return CBORSimple(arg), nil
+	}
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+type cborCodec struct{}
+
+func (cborCodec) Name() string {
+	return "cbor"
+}
+
+func (cborCodec) Marshal(v any) ([]byte, error) {
+	return MarshalCBOR(v)
+}
+
+func (cborCodec) Unmarshal(data []byte, v any) error {
+	return UnmarshalCBOR(data, v)
+}
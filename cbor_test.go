@@ -0,0 +1,285 @@
+package conv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Examples of encoded CBOR data items in RFC 8949 Appendix A
+var cborAppendixExamples = []struct {
+	Value any
+	Hex   string
+}{
+	{int64(0), "00"},
+	{int64(1), "01"},
+	{int64(10), "0a"},
+	{int64(23), "17"},
+	{int64(24), "1818"},
+	{int64(25), "1819"},
+	{int64(100), "1864"},
+	{int64(1000), "1903e8"},
+	{int64(1000000), "1a000f4240"},
+	{int64(1000000000000), "1b000000e8d4a51000"},
+	{uint64(18446744073709551615), "1bffffffffffffffff"},
+	{int64(-1), "20"},
+	{int64(-10), "29"},
+	{int64(-100), "3863"},
+	{int64(-1000), "3903e7"},
+	{0.0, "f90000"},
+	{math.Copysign(0, -1), "f98000"},
+	{1.0, "f93c00"},
+	{1.1, "fb3ff199999999999a"},
+	{1.5, "f93e00"},
+	{65504.0, "f97bff"},
+	{100000.0, "fa47c35000"},
+	{3.4028234663852886e+38, "fa7f7fffff"},
+	{1.0e+300, "fb7e37e43c8800759c"},
+	{5.960464477539063e-8, "f90001"},
+	{0.00006103515625, "f90400"},
+	{-4.0, "f9c400"},
+	{-4.1, "fbc010666666666666"},
+	{math.Inf(1), "f97c00"},
+	{math.Inf(-1), "f9fc00"},
+	{false, "f4"},
+	{true, "f5"},
+	{nil, "f6"},
+	{CBORSimple(16), "f0"},
+	{CBORSimple(255), "f8ff"},
+	{time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC), "c074323031332d30332d32315432303a30343a30305a"},
+	{time.Unix(1363896240, 0).UTC(), "c11a514b67b0"},
+	{time.Unix(1363896240, 500000000).UTC(), "c1fb41d452d9ec200000"},
+	{CBORTag{Number: 23, Content: []byte{1, 2, 3, 4}}, "d74401020304"},
+	{CBORTag{Number: 24, Content: []byte{0x64, 0x49, 0x45, 0x54, 0x46}}, "d818456449455446"},
+	{CBORTag{Number: 32, Content: "http://www.example.com"}, "d82076687474703a2f2f7777772e6578616d706c652e636f6d"},
+	{[]byte{}, "40"},
+	{[]byte{1, 2, 3, 4}, "4401020304"},
+	{"", "60"},
+	{"a", "6161"},
+	{"IETF", "6449455446"},
+	{"\"\\", "62225c"},
+	{"ü", "62c3bc"},
+	{"水", "63e6b0b4"},
+	{"\U00010151", "64f0908591"},
+	{[]any{}, "80"},
+	{[]any{int64(1), int64(2), int64(3)}, "83010203"},
+	{[]any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}, "8301820203820405"},
+	{map[string]any{}, "a0"},
+	{map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}, "a26161016162820203"},
+	{[]any{"a", map[string]any{"b": "c"}}, "826161a161626163"},
+	{map[string]any{"a": "A", "b": "B", "c": "C", "d": "D", "e": "E"}, "a56161614161626142616361436164614461656145"},
+}
+
+// Examples which are decoded only, as the encoder uses definite lengths or integer keys are decoded as strings
+var cborAppendixDecodeExamples = []struct {
+	Value any
+	Hex   string
+}{
+	{mustParseBigInt("18446744073709551616"), "c249010000000000000000"},
+	{mustParseBigInt("-18446744073709551617"), "c349010000000000000000"},
+	{mustParseBigInt("-18446744073709551616"), "3bffffffffffffffff"},
+	{math.NaN(), "f97e00"},
+	{math.NaN(), "fa7fc00000"},
+	{math.Inf(1), "fa7f800000"},
+	{math.Inf(-1), "fbfff0000000000000"},
+	{nil, "f7"},
+	{map[string]any{"1": int64(2), "3": int64(4)}, "a201020304"},
+	{[]byte{1, 2, 3, 4, 5}, "5f42010243030405ff"},
+	{"streaming", "7f657374726561646d696e67ff"},
+	{[]any{}, "9fff"},
+	{[]any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}, "9f018202039f0405ffff"},
+	{[]any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}, "83018202039f0405ff"},
+	{[]any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}, "83019f0203ff820405"},
+	{map[string]any{"a": int64(1), "b": []any{int64(2), int64(3)}}, "bf61610161629f0203ffff"},
+	{[]any{"a", map[string]any{"b": "c"}}, "826161bf61626163ff"},
+	{map[string]any{"Fun": true, "Amt": int64(-2)}, "bf6346756ef563416d7421ff"},
+}
+
+func mustParseBigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic(s)
+	}
+	return n
+}
+
+func cborValueEqual(a, b any) bool {
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			return fa == fb && math.Signbit(fa) == math.Signbit(fb) || math.IsNaN(fa) && math.IsNaN(fb)
+		}
+	}
+	if na, ok := a.(*big.Int); ok {
+		nb, ok := b.(*big.Int)
+		return ok && na.Cmp(nb) == 0
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func TestUnmarshalCBORAppendix(t *testing.T) {
+	examples := append(cborAppendixExamples, cborAppendixDecodeExamples...)
+	for _, e := range examples {
+		data, _ := hex.DecodeString(e.Hex)
+		var v any
+		if err := UnmarshalCBOR(data, &v); err != nil {
+			t.Errorf("%s: %v", e.Hex, err)
+			continue
+		}
+		if !cborValueEqual(e.Value, v) {
+			t.Errorf("%s: expect %#v, got %#v", e.Hex, e.Value, v)
+		}
+	}
+}
+
+func TestMarshalCBORAppendix(t *testing.T) {
+	for _, e := range cborAppendixExamples {
+		b, err := MarshalCBOR(e.Value, func(options *CBOROptions) {
+			options.Canonical = true
+			if strings.HasPrefix(e.Hex, "c0") {
+				options.TimeFormat = CBORTimeString
+			}
+		})
+		if err != nil {
+			t.Errorf("%#v: %v", e.Value, err)
+			continue
+		}
+		if s := hex.EncodeToString(b); s != e.Hex {
+			t.Errorf("%#v: expect %s, got %s", e.Value, e.Hex, s)
+		}
+	}
+
+	for _, s := range []string{"18446744073709551616", "-18446744073709551617"} {
+		b, err := MarshalCBOR(mustParseBigInt(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n big.Int
+		if err = UnmarshalCBOR(b, &n); err != nil || n.String() != s {
+			t.Fatal(n.String(), err)
+		}
+	}
+}
+
+func TestCBORStruct(t *testing.T) {
+	type Reading struct {
+		DeviceID  string    `cbor:"device"`
+		Values    []float32 `cbor:"values"`
+		Battery   uint8     `cbor:"battery,omitempty"`
+		Timestamp time.Time `cbor:"ts"`
+		Note      string
+	}
+
+	r := Reading{
+		DeviceID:  "sensor-1",
+		Values:    []float32{1.5, -2},
+		Timestamp: time.Unix(1700000000, 0).UTC(),
+		Note:      "ok",
+	}
+
+	b1, err := MarshalCBOR(r, func(options *CBOROptions) { options.Canonical = true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2, err := MarshalCBOR(map[string]any{"device": "sensor-1", "values": []float64{1.5, -2}, "ts": r.Timestamp, "Note": "ok"}, func(options *CBOROptions) {
+		options.Canonical = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(b1) != hex.EncodeToString(b2) {
+		t.Fatalf("expect deterministic encoding %x, got %x", b2, b1)
+	}
+
+	var r2 Reading
+	if err = UnmarshalCBOR(b1, &r2); err != nil {
+		t.Fatal(err)
+	}
+	if r2.DeviceID != r.DeviceID || r2.Note != r.Note || !r2.Timestamp.Equal(r.Timestamp) {
+		t.Fatalf("%#v", r2)
+	}
+	if diff := diffSlice(r.Values, r2.Values); diff != "" {
+		t.Fatal(diff)
+	}
+
+	type Meta struct {
+		Site string `cbor:"site"`
+	}
+	type Embedded struct {
+		*Meta
+		Reading
+	}
+	for _, e := range []Embedded{{Reading: r}, {Meta: &Meta{Site: "lab"}, Reading: r}} {
+		b, err := MarshalCBOR(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]any
+		if err = UnmarshalCBOR(b, &m); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m["site"]; ok != (e.Meta != nil) || m["device"] != "sensor-1" || m["Note"] != "ok" {
+			t.Fatalf("%#v", m)
+		}
+	}
+}
+
+func TestCBORCodec(t *testing.T) {
+	r := NewCodecRegistry()
+	r.Register(CBORCodec)
+	if err := r.SetFallback("cbor"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := r.Marshal([]int{1, 2, 3})
+	if err != nil || hex.EncodeToString(data) != "83010203" {
+		t.Fatal(hex.EncodeToString(data), err)
+	}
+	var l []int
+	if err = r.Unmarshal(data, &l); err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]int{1, 2, 3}, l); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if _, ok := DefaultCodecRegistry.Codec("cbor"); !ok {
+		t.Fatal("cbor is not registered")
+	}
+}
+
+func TestUnmarshalCBORBad(t *testing.T) {
+	for _, s := range []string{"", "18", "ff", "1c", "5f01ff", "8201", "a1", "0000"} {
+		data, _ := hex.DecodeString(s)
+		var v any
+		if err := UnmarshalCBOR(data, &v); err == nil {
+			t.Errorf("%s: should fail", s)
+		}
+	}
+}
+
+func TestUnmarshalCBORDepth(t *testing.T) {
+	nested := func(head byte, depth int) []byte {
+		return append(bytes.Repeat([]byte{head}, depth), 0x01)
+	}
+
+	for _, head := range []byte{0x81, 0xc6} {
+		var v any
+		if err := UnmarshalCBOR(nested(head, maxDecodeDepth), &v); err != nil {
+			t.Fatalf("0x%x: %v", head, err)
+		}
+		if err := UnmarshalCBOR(nested(head, maxDecodeDepth+1), &v); err == nil {
+			t.Fatalf("0x%x: should fail", head)
+		}
+		if err := UnmarshalCBOR(nested(head, 20_000_000), &v); err == nil {
+			t.Fatalf("0x%x: should fail", head)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package conv
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SliceToChan sends elements of a to the returned channel, which is closed after all elements are sent or ctx is done
+func SliceToChan[E any](ctx context.Context, a []E) <-chan E {
+	return IteratorToChan(ctx, SliceIterator(a))
+}
+
+// ListToChan sends elements of l to the returned channel, which is closed after all elements are sent or ctx is done
+// l must not be modified until the channel is closed
+func ListToChan[E any](ctx context.Context, l *List[E]) <-chan E {
+	return IteratorToChan(ctx, ListIterator(l))
+}
+
+// ChanToSlice receives elements from ch until ch is closed
+// If ctx is done before that, the received elements are returned along with ctx.Err()
+func ChanToSlice[E any](ctx context.Context, ch <-chan E) ([]E, error) {
+	var a []E
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				return a, nil
+			}
+			a = append(a, e)
+		case <-ctx.Done():
+			return a, ctx.Err()
+		}
+	}
+}
+
+// BatchChan groups elements from ch into batches of at most size elements
+// A batch is sent once it's full, or maxWait has passed since its first element was received if maxWait is positive.
+// The returned channel is closed after the remaining elements are sent when ch is closed.
+// It panics if size is not positive
+func BatchChan[E any](ch <-chan E, size int, maxWait time.Duration) <-chan []E {
+	if size <= 0 {
+		panic("size must be positive")
+	}
+	out := make(chan []E)
+	go func() {
+		defer close(out)
+		var (
+			batch   []E
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		flush := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) > 0 {
+				out <- batch
+				batch = nil
+			}
+		}
+		for {
+			select {
+			case e, ok := <-ch:
+				if !ok {
+					flush()
+					return
+				}
+				if batch == nil {
+					batch = make([]E, 0, size)
+					if maxWait > 0 {
+						timer = time.NewTimer(maxWait)
+						timeout = timer.C
+					}
+				}
+				batch = append(batch, e)
+				if len(batch) == size {
+					flush()
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				flush()
+			}
+		}
+	}()
+	return out
+}
+
+// FanIn merges elements from chs into the returned channel, which is closed after all chs are closed or ctx is done
+func FanIn[E any](ctx context.Context, chs ...<-chan E) <-chan E {
+	out := make(chan E)
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+	for _, ch := range chs {
+		go func(ch <-chan E) {
+			defer wg.Done()
+			for e := range ch {
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// FanOut distributes elements from ch to n channels, each element is received by whichever channel is ready first
+// The returned channels are closed after ch is closed or ctx is done.
+// It panics if n is not positive
+func FanOut[E any](ctx context.Context, ch <-chan E, n int) []<-chan E {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	outs := make([]<-chan E, n)
+	for i := range outs {
+		out := make(chan E)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for {
+				select {
+				case e, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case out <- e:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// Iterator iterates elements lazily
+// Next returns the next element, or false if there are no more elements
+type Iterator[E any] interface {
+	Next() (E, bool)
+}
+
+// IteratorFunc is a function which implements Iterator
+type IteratorFunc[E any] func() (E, bool)
+
+func (f IteratorFunc[E]) Next() (E, bool) {
+	return f()
+}
+
+// SliceIterator iterates elements of a
+func SliceIterator[E any](a []E) Iterator[E] {
+	i := 0
+	return IteratorFunc[E](func() (E, bool) {
+		if i >= len(a) {
+			var zero E
+			return zero, false
+		}
+		i++
+		return a[i-1], true
+	})
+}
+
+// ListIterator iterates elements of l from front to back
+func ListIterator[E any](l *List[E]) Iterator[E] {
+	elem := l.List().Front()
+	return IteratorFunc[E](func() (E, bool) {
+		if elem == nil {
+			var zero E
+			return zero, false
+		}
+		e := elem.Value.(E)
+		elem = elem.Next()
+		return e, true
+	})
+}
+
+// ChanIterator iterates elements received from ch until ch is closed
+func ChanIterator[E any](ch <-chan E) Iterator[E] {
+	return IteratorFunc[E](func() (E, bool) {
+		e, ok := <-ch
+		return e, ok
+	})
+}
+
+// MapIterator iterates entries of m in unspecified order
+func MapIterator[K comparable, V any](m map[K]V) Iterator[Pair[K, V]] {
+	iter := reflect.ValueOf(m).MapRange()
+	return IteratorFunc[Pair[K, V]](func() (Pair[K, V], bool) {
+		if !iter.Next() {
+			return Pair[K, V]{}, false
+		}
+		return Pair[K, V]{First: iter.Key().Interface().(K), Second: iter.Value().Interface().(V)}, true
+	})
+}
+
+// TransformIterator lazily transforms elements of it
+func TransformIterator[E1 any, E2 any](it Iterator[E1], transformer func(e E1) E2) Iterator[E2] {
+	return IteratorFunc[E2](func() (E2, bool) {
+		e, ok := it.Next()
+		if !ok {
+			var zero E2
+			return zero, false
+		}
+		return transformer(e), true
+	})
+}
+
+// FilterIterator lazily skips elements of it for which pred returns false
+func FilterIterator[E any](it Iterator[E], pred func(e E) bool) Iterator[E] {
+	return IteratorFunc[E](func() (E, bool) {
+		for {
+			e, ok := it.Next()
+			if !ok || pred(e) {
+				return e, ok
+			}
+		}
+	})
+}
+
+// TakeIterator stops after n elements of it
+func TakeIterator[E any](it Iterator[E], n int) Iterator[E] {
+	return IteratorFunc[E](func() (E, bool) {
+		if n <= 0 {
+			var zero E
+			return zero, false
+		}
+		n--
+		return it.Next()
+	})
+}
+
+// IteratorToSlice collects all elements of it
+func IteratorToSlice[E any](it Iterator[E]) []E {
+	var a []E
+	for {
+		e, ok := it.Next()
+		if !ok {
+			return a
+		}
+		a = append(a, e)
+	}
+}
+
+// IteratorToChan sends elements of it to the returned channel, which is closed after it is exhausted or ctx is done
+func IteratorToChan[E any](ctx context.Context, it Iterator[E]) <-chan E {
+	out := make(chan E)
+	go func() {
+		defer close(out)
+		for {
+			e, ok := it.Next()
+			if !ok {
+				return
+			}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,142 @@
+package conv
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSliceToChan(t *testing.T) {
+	ctx := context.Background()
+	a, err := ChanToSlice(ctx, SliceToChan(ctx, []int{1, 2, 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]int{1, 2, 3}, a); diff != "" {
+		t.Fatal(diff)
+	}
+
+	a, err = ChanToSlice(ctx, ListToChan(ctx, NewList(4, 5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]int{4, 5}, a); diff != "" {
+		t.Fatal(diff)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	ch := make(chan int, 1)
+	ch <- 1
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	a, err = ChanToSlice(cctx, ch)
+	if !errors.Is(err, context.Canceled) || len(a) != 1 {
+		t.Fatal(a, err)
+	}
+
+	cctx, cancel = context.WithCancel(ctx)
+	out := SliceToChan(cctx, []int{1, 2, 3})
+	<-out
+	cancel()
+	for range out {
+	}
+}
+
+func TestBatchChan(t *testing.T) {
+	t.Run("Size", func(t *testing.T) {
+		ch := make(chan int)
+		go func() {
+			for i := 1; i <= 5; i++ {
+				ch <- i
+			}
+			close(ch)
+		}()
+		var batches [][]int
+		for b := range BatchChan(ch, 2, 0) {
+			batches = append(batches, b)
+		}
+		if len(batches) != 3 || len(batches[2]) != 1 || batches[2][0] != 5 {
+			t.Fatal(batches)
+		}
+	})
+
+	t.Run("MaxWait", func(t *testing.T) {
+		ch := make(chan int)
+		batches := BatchChan(ch, 10, 10*time.Millisecond)
+		ch <- 1
+		ch <- 2
+		select {
+		case b := <-batches:
+			if diff := diffSlice([]int{1, 2}, b); diff != "" {
+				t.Fatal(diff)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+		close(ch)
+		if _, ok := <-batches; ok {
+			t.Fatal("should be closed")
+		}
+	})
+}
+
+func TestFanInFanOut(t *testing.T) {
+	ctx := context.Background()
+	outs := FanOut(ctx, SliceToChan(ctx, []int{1, 2, 3, 4, 5, 6}), 3)
+	if len(outs) != 3 {
+		t.Fatal(len(outs))
+	}
+	a, err := ChanToSlice(ctx, FanIn(ctx, outs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(a)
+	if diff := diffSlice([]int{1, 2, 3, 4, 5, 6}, a); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if _, ok := <-FanIn[int](ctx); ok {
+		t.Fatal("should be closed")
+	}
+}
+
+func TestIterator(t *testing.T) {
+	var calls int
+	it := TransformIterator(SliceIterator([]int{1, 2, 3, 4, 5, 6}), func(e int) string {
+		calls++
+		return strconv.Itoa(e * 10)
+	})
+	it = FilterIterator(it, func(e string) bool { return e != "20" })
+	if diff := diffSlice([]string{"10", "30"}, IteratorToSlice(TakeIterator(it, 2))); diff != "" {
+		t.Fatal(diff)
+	}
+	if calls != 3 {
+		t.Fatal(calls)
+	}
+
+	ch := make(chan int, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	if diff := diffSlice([]int{1, 2}, IteratorToSlice(ChanIterator(ch))); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if diff := diffSlice([]int{7, 8}, IteratorToSlice(ListIterator(NewList(7, 8)))); diff != "" {
+		t.Fatal(diff)
+	}
+
+	pairs := IteratorToSlice(MapIterator(map[string]int{"a": 1, "b": 2}))
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].First < pairs[j].First })
+	if diff := diffSlice([]Pair[string, int]{{"a", 1}, {"b", 2}}, pairs); diff != "" {
+		t.Fatal(diff)
+	}
+	if IteratorToSlice(SliceIterator([]int{})) != nil {
+		t.Fatal("should be nil")
+	}
+}
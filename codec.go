@@ -0,0 +1,409 @@
+package conv
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrNoCodec means no codec is found for the value
+var ErrNoCodec = errors.New("no codec")
+
+// Codec marshals values into bytes and unmarshals bytes into values
+type Codec interface {
+	// Name identifies the codec in CodecRegistry and in envelopes, its length must be less than 256
+	Name() string
+	Marshal(v any) ([]byte, error)
+	// Unmarshal parses data and stores the result in the value pointed to by v
+	Unmarshal(data []byte, v any) error
+}
+
+// CodecMatcher can be implemented by codecs which natively support only some types.
+// While probing, CodecRegistry skips codecs which do not match the value.
+// Codecs without CodecMatcher match any value.
+type CodecMatcher interface {
+	MatchMarshal(v any) bool
+	// MatchUnmarshal reports whether the codec can unmarshal into v which is a pointer
+	MatchUnmarshal(v any) bool
+}
+
+var (
+	// RawCodec handles []byte and types convertible from/to []byte, e.g. string
+	RawCodec Codec = rawCodec{}
+	// BinaryCodec handles encoding.BinaryMarshaler and encoding.BinaryUnmarshaler
+	BinaryCodec Codec = binaryCodec{}
+	// TextCodec handles encoding.TextMarshaler and encoding.TextUnmarshaler
+	TextCodec Codec = textCodec{}
+	// JSONCodec matches json.Marshaler and json.Unmarshaler, and can marshal any value with encoding/json
+	JSONCodec Codec = jsonCodec{}
+	// GobCodec matches gob.GobEncoder and gob.GobDecoder whose methods are called directly,
+	// and can marshal any other value with a gob stream
+	GobCodec Codec = gobCodec{}
+)
+
+// DefaultCodecRegistry is used by UnsafeMarshal and UnsafeUnmarshal
+// It probes raw, binary, text, json and gob codecs in order, and falls back to json codec.
+// CBORCodec is registered but not probed.
+var DefaultCodecRegistry = newDefaultCodecRegistry()
+
+func newDefaultCodecRegistry() *CodecRegistry {
+	r := NewCodecRegistry(RawCodec, BinaryCodec, TextCodec, JSONCodec, GobCodec)
+	r.Register(CBORCodec)
+	r.fallback = JSONCodec
+	return r
+}
+
+// CodecRegistry selects codecs to marshal and unmarshal values
+// A codec is selected by probing codecs in order, or falls back to the fallback codec if none matches.
+// In envelope mode, Marshal prefixes data with the name of the selected codec,
+// and Unmarshal selects the codec by the prefix.
+type CodecRegistry struct {
+	mu       sync.RWMutex
+	codecs   map[string]Codec
+	order    []Codec
+	fallback Codec
+	envelope bool
+}
+
+// NewCodecRegistry creates a registry with codecs which are registered and probed in order
+func NewCodecRegistry(codecs ...Codec) *CodecRegistry {
+	r := &CodecRegistry{
+		codecs: make(map[string]Codec, len(codecs)),
+	}
+	for _, c := range codecs {
+		r.codecs[c.Name()] = c
+	}
+	r.order = append(r.order, codecs...)
+	return r
+}
+
+// Register adds c to r, replacing the codec of the same name
+// c is not probed unless its name is passed to SetProbeOrder
+func (r *CodecRegistry) Register(c Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name := c.Name()
+	r.codecs[name] = c
+	for i, o := range r.order {
+		if o.Name() == name {
+			r.order[i] = c
+		}
+	}
+	if r.fallback != nil && r.fallback.Name() == name {
+		r.fallback = c
+	}
+}
+
+// Codec returns the registered codec with name
+func (r *CodecRegistry) Codec(name string) (Codec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.codecs[name]
+	return c, ok
+}
+
+// SetProbeOrder sets names of registered codecs to probe in order
+func (r *CodecRegistry) SetProbeOrder(names ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order := make([]Codec, len(names))
+	for i, name := range names {
+		c, ok := r.codecs[name]
+		if !ok {
+			return fmt.Errorf("codec %s is not registered", name)
+		}
+		order[i] = c
+	}
+	r.order = order
+	return nil
+}
+
+// SetFallback sets the registered codec used when no codec matches while probing
+// Empty name disables the fallback.
+func (r *CodecRegistry) SetFallback(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == "" {
+		r.fallback = nil
+		return nil
+	}
+	c, ok := r.codecs[name]
+	if !ok {
+		return fmt.Errorf("codec %s is not registered", name)
+	}
+	r.fallback = c
+	return nil
+}
+
+// SetEnvelope enables or disables envelope mode
+func (r *CodecRegistry) SetEnvelope(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.envelope = enabled
+}
+
+func (r *CodecRegistry) Marshal(v any) ([]byte, error) {
+	r.mu.RLock()
+	c := r.probe(v, CodecMatcher.MatchMarshal)
+	envelope := r.envelope
+	r.mu.RUnlock()
+
+	if c == nil {
+		return nil, fmt.Errorf("cannot marshal %T: %w", v, ErrNoCodec)
+	}
+
+	data, err := c.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Name(), err)
+	}
+
+	if !envelope {
+		return data, nil
+	}
+
+	name := c.Name()
+	if len(name) > 255 {
+		return nil, fmt.Errorf("codec name is too long: %s", name)
+	}
+	res := make([]byte, 0, 1+len(name)+len(data))
+	res = append(res, byte(len(name)))
+	res = append(res, name...)
+	return append(res, data...), nil
+}
+
+func (r *CodecRegistry) Unmarshal(data []byte, v any) error {
+	if reflect.ValueOf(v).Kind() != reflect.Pointer {
+		return fmt.Errorf("cannot unmarshal to non pointer type: %T", v)
+	}
+
+	r.mu.RLock()
+	envelope := r.envelope
+	var c Codec
+	if !envelope {
+		c = r.probe(v, CodecMatcher.MatchUnmarshal)
+	}
+	r.mu.RUnlock()
+
+	if envelope {
+		if len(data) == 0 || len(data) < 1+int(data[0]) {
+			return errors.New("invalid envelope")
+		}
+		name := string(data[1 : 1+data[0]])
+		data = data[1+len(name):]
+		var ok bool
+		if c, ok = r.Codec(name); !ok {
+			return fmt.Errorf("codec %s is not registered: %w", name, ErrNoCodec)
+		}
+	}
+
+	if c == nil {
+		return fmt.Errorf("cannot unmarshal into %T: %w", v, ErrNoCodec)
+	}
+
+	if err := c.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", c.Name(), err)
+	}
+	return nil
+}
+
+func (r *CodecRegistry) probe(v any, match func(CodecMatcher, any) bool) Codec {
+	for _, c := range r.order {
+		if m, ok := c.(CodecMatcher); !ok || match(m, v) {
+			return c
+		}
+	}
+	return r.fallback
+}
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+type rawCodec struct{}
+
+func (rawCodec) Name() string {
+	return "raw"
+}
+
+func (rawCodec) MatchMarshal(v any) bool {
+	return v != nil && reflect.TypeOf(v).ConvertibleTo(bytesType)
+}
+
+func (rawCodec) MatchUnmarshal(v any) bool {
+	return bytesType.ConvertibleTo(reflect.TypeOf(v).Elem())
+}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	if data, ok := v.([]byte); ok {
+		return data, nil
+	}
+	if v == nil || !reflect.TypeOf(v).ConvertibleTo(bytesType) {
+		return nil, fmt.Errorf("cannot convert %T to []byte", v)
+	}
+	return reflect.ValueOf(v).Convert(bytesType).Bytes(), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	if p, ok := v.(*[]byte); ok {
+		*p = data
+		return nil
+	}
+	dv := reflect.ValueOf(v).Elem()
+	if !bytesType.ConvertibleTo(dv.Type()) {
+		return fmt.Errorf("cannot convert []byte to %v", dv.Type())
+	}
+	if dv.Kind() == reflect.Array {
+		return fillByteArray(dv, data, &ByteArrayOptions{Padding: PadNone})
+	}
+	dv.Set(reflect.ValueOf(data).Convert(dv.Type()))
+	return nil
+}
+
+type binaryCodec struct{}
+
+func (binaryCodec) Name() string {
+	return "binary"
+}
+
+func (binaryCodec) MatchMarshal(v any) bool {
+	_, ok := v.(encoding.BinaryMarshaler)
+	return ok
+}
+
+func (binaryCodec) MatchUnmarshal(v any) bool {
+	return implementsOnPointerChain[encoding.BinaryUnmarshaler](v)
+}
+
+func (binaryCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T is not encoding.BinaryMarshaler", v)
+	}
+	return m.MarshalBinary()
+}
+
+func (binaryCodec) Unmarshal(data []byte, v any) error {
+	return unmarshalOnPointerChain(v, func(u encoding.BinaryUnmarshaler) error {
+		return u.UnmarshalBinary(data)
+	})
+}
+
+type textCodec struct{}
+
+func (textCodec) Name() string {
+	return "text"
+}
+
+func (textCodec) MatchMarshal(v any) bool {
+	_, ok := v.(encoding.TextMarshaler)
+	return ok
+}
+
+func (textCodec) MatchUnmarshal(v any) bool {
+	return implementsOnPointerChain[encoding.TextUnmarshaler](v)
+}
+
+func (textCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(encoding.TextMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T is not encoding.TextMarshaler", v)
+	}
+	return m.MarshalText()
+}
+
+func (textCodec) Unmarshal(data []byte, v any) error {
+	return unmarshalOnPointerChain(v, func(u encoding.TextUnmarshaler) error {
+		return u.UnmarshalText(data)
+	})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+func (jsonCodec) MatchMarshal(v any) bool {
+	_, ok := v.(json.Marshaler)
+	return ok
+}
+
+func (jsonCodec) MatchUnmarshal(v any) bool {
+	return implementsOnPointerChain[json.Unmarshaler](v)
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Name() string {
+	return "gob"
+}
+
+func (gobCodec) MatchMarshal(v any) bool {
+	_, ok := v.(gob.GobEncoder)
+	return ok
+}
+
+func (gobCodec) MatchUnmarshal(v any) bool {
+	return implementsOnPointerChain[gob.GobDecoder](v)
+}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	if e, ok := v.(gob.GobEncoder); ok {
+		return e.GobEncode()
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	if implementsOnPointerChain[gob.GobDecoder](v) {
+		return unmarshalOnPointerChain(v, func(d gob.GobDecoder) error {
+			return d.GobDecode(data)
+		})
+	}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// implementsOnPointerChain reports whether any pointer type on the chain of pointer v implements U
+func implementsOnPointerChain[U any](v any) bool {
+	ut := reflect.TypeOf((*U)(nil)).Elem()
+	for t := reflect.TypeOf(v); t != nil && t.Kind() == reflect.Pointer; t = t.Elem() {
+		if t.Implements(ut) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalOnPointerChain allocates the value pointed to by v,
+// calls fn with the first pointer on the chain implementing U,
+// and assigns the allocated value to v if fn succeeds
+func unmarshalOnPointerChain[U any](v any, fn func(U) error) error {
+	// nv is a pointer of the same type
+	nv := DeepNew(reflect.TypeOf(v).Elem())
+	for p := nv; p.Kind() == reflect.Pointer && !p.IsNil(); p = p.Elem() {
+		if u, ok := p.Interface().(U); ok {
+			if err := fn(u); err != nil {
+				return err
+			}
+			// v is a parameter, it cannot be set, the value it points to can be set
+			reflect.ValueOf(v).Elem().Set(nv.Elem())
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot unmarshal into: %T", v)
+}
@@ -0,0 +1,136 @@
+package conv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type upperCodec struct{}
+
+func (upperCodec) Name() string {
+	return "upper"
+}
+
+func (upperCodec) Marshal(v any) ([]byte, error) {
+	return []byte(strings.ToUpper(MustToString(v))), nil
+}
+
+func (upperCodec) Unmarshal(data []byte, v any) error {
+	*(v.(*string)) = strings.ToLower(string(data))
+	return nil
+}
+
+func TestUnsafeMarshalPlainStruct(t *testing.T) {
+	type Point struct {
+		X, Y int
+	}
+
+	data, err := UnsafeMarshal(Point{X: 1, Y: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"X":1,"Y":2}` {
+		t.Fatal(string(data))
+	}
+
+	var p Point
+	if err = UnsafeUnmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.X != 1 || p.Y != 2 {
+		t.Fatal(p)
+	}
+
+	if err = UnsafeUnmarshal(data, p); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestUnsafeUnmarshalByteArray(t *testing.T) {
+	var a [2]byte
+	if err := UnsafeUnmarshal([]byte{1, 2}, &a); err != nil || a != [2]byte{1, 2} {
+		t.Fatal(a, err)
+	}
+	if err := UnsafeUnmarshal([]byte{1}, &a); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestCodecRegistry(t *testing.T) {
+	t.Run("ProbeOrder", func(t *testing.T) {
+		r := NewCodecRegistry(RawCodec, JSONCodec)
+		r.Register(upperCodec{})
+		data, err := r.Marshal("hello")
+		if err != nil || string(data) != "hello" {
+			t.Fatal(string(data), err)
+		}
+
+		if err = r.SetProbeOrder("upper", "raw"); err != nil {
+			t.Fatal(err)
+		}
+		data, err = r.Marshal("hello")
+		if err != nil || string(data) != "HELLO" {
+			t.Fatal(string(data), err)
+		}
+
+		if err = r.SetProbeOrder("unknown"); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+
+	t.Run("NoCodec", func(t *testing.T) {
+		r := NewCodecRegistry(RawCodec, JSONCodec)
+		_, err := r.Marshal(struct{}{})
+		if !errors.Is(err, ErrNoCodec) {
+			t.Fatal(err)
+		}
+
+		if err = r.SetFallback("json"); err != nil {
+			t.Fatal(err)
+		}
+		data, err := r.Marshal(struct{}{})
+		if err != nil || string(data) != "{}" {
+			t.Fatal(string(data), err)
+		}
+	})
+
+	t.Run("Envelope", func(t *testing.T) {
+		type Item struct {
+			Name  string
+			Count int
+		}
+
+		r := NewCodecRegistry(RawCodec, TextCodec, JSONCodec, GobCodec)
+		_ = r.SetFallback("gob")
+		r.SetEnvelope(true)
+
+		data, err := r.Marshal(Item{Name: "apple", Count: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(data), "\x03gob") {
+			t.Fatalf("%q", data)
+		}
+
+		var item Item
+		if err = r.Unmarshal(data, &item); err != nil {
+			t.Fatal(err)
+		}
+		if item.Name != "apple" || item.Count != 3 {
+			t.Fatal(item)
+		}
+
+		data, err = r.Marshal([]byte("raw"))
+		if err != nil || string(data) != "\x03rawraw" {
+			t.Fatalf("%q %v", data, err)
+		}
+
+		if err = r.Unmarshal([]byte("\x07unknownxx"), &item); !errors.Is(err, ErrNoCodec) {
+			t.Fatal(err)
+		}
+		if err = r.Unmarshal([]byte("\x09gob"), &item); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
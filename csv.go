@@ -0,0 +1,378 @@
+package conv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CSVOptions controls how CSV records are mapped to and from structs
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' by default
+	Comma rune
+
+	// TagName is the key of struct tags, "csv" by default
+	TagName string
+
+	// FieldNameMatcher matches a header to the name of a field without tag
+	// By default, names are compared case-insensitively ignoring '_' and '-'
+	FieldNameMatcher FieldNameMatcher
+
+	// TimeLayouts are tried in order to parse time.Time, time.RFC3339 by default
+	// The first layout is used to format time.Time
+	TimeLayouts []string
+
+	// TrueValues and FalseValues are case-insensitive words of bool, e.g. yes and no
+	// Cells not in the vocabulary are parsed with ToBool
+	// The first words are used to format bool
+	TrueValues  []string
+	FalseValues []string
+}
+
+func newCSVOptions(optFns []func(options *CSVOptions)) *CSVOptions {
+	options := &CSVOptions{
+		Comma:            ',',
+		TagName:          "csv",
+		FieldNameMatcher: looseFieldNameMatcher{},
+	}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	if len(options.TimeLayouts) == 0 {
+		options.TimeLayouts = []string{time.RFC3339}
+	}
+	return options
+}
+
+// CSVError describes a cell which cannot be converted
+// Row and Column are 1-based as in spreadsheets, the header is row 1
+type CSVError struct {
+	Row    int
+	Column int
+	Header string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("row %d, column %d (%s): %v", e.Row, e.Column, e.Header, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+// CSVDecoder reads CSV records with a header row into structs or maps
+type CSVDecoder struct {
+	r       *csv.Reader
+	options *CSVOptions
+	header  []string
+	row     int
+	fields  map[reflect.Type][][]int
+}
+
+// NewCSVDecoder creates a decoder, the first record of r is the header
+func NewCSVDecoder(r io.Reader, optFns ...func(options *CSVOptions)) *CSVDecoder {
+	options := newCSVOptions(optFns)
+	cr := csv.NewReader(r)
+	cr.Comma = options.Comma
+	cr.ReuseRecord = true
+	return &CSVDecoder{
+		r:       cr,
+		options: options,
+		fields:  make(map[reflect.Type][][]int),
+	}
+}
+
+// Header returns the header row, reading it if no record has been decoded
+func (d *CSVDecoder) Header() ([]string, error) {
+	if d.header == nil {
+		record, err := d.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		d.header = make([]string, len(record))
+		for i, h := range record {
+			d.header[i] = strings.TrimSpace(h)
+		}
+		// excel may add byte order mark
+		if len(d.header) > 0 {
+			d.header[0] = strings.TrimPrefix(d.header[0], "\ufeff")
+		}
+		d.row = 1
+	}
+	return d.header, nil
+}
+
+// Decode reads the next record into dst which is a pointer to struct, map[string]string or map[string]any
+// Columns without matched fields are ignored, empty cells leave non-string fields unchanged.
+// It returns io.EOF if there is no more record
+func (d *CSVDecoder) Decode(dst any) error {
+	header, err := d.Header()
+	if err != nil {
+		return err
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("cannot decode into %T", dst)
+	}
+	dv = dv.Elem()
+
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	d.row++
+
+	switch dv.Kind() {
+	case reflect.Map:
+		if dv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot decode into %T", dst)
+		}
+		if dv.IsNil() {
+			dv.Set(reflect.MakeMap(dv.Type()))
+		}
+		for i, cell := range record {
+			if i >= len(header) {
+				break
+			}
+			ev := reflect.New(dv.Type().Elem()).Elem()
+			if err = d.setCell(ev, cell); err != nil {
+				return &CSVError{Row: d.row, Column: i + 1, Header: header[i], Err: err}
+			}
+			dv.SetMapIndex(reflect.ValueOf(header[i]).Convert(dv.Type().Key()), ev)
+		}
+		return nil
+	case reflect.Struct:
+		fields := d.structFields(dv.Type())
+		for i, cell := range record {
+			if i >= len(fields) || fields[i] == nil {
+				continue
+			}
+			fv, err := fieldByIndexAlloc(dv, fields[i])
+			if err == nil {
+				err = d.setCell(fv, cell)
+			}
+			if err != nil {
+				return &CSVError{Row: d.row, Column: i + 1, Header: header[i], Err: err}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("cannot decode into %T", dst)
+	}
+}
+
+// structFields returns the index of the field matched by each column
+func (d *CSVDecoder) structFields(t reflect.Type) [][]int {
+	if fields, ok := d.fields[t]; ok {
+		return fields
+	}
+	all := csvStructFields(t, d.options.TagName)
+	fields := make([][]int, len(d.header))
+	for i, h := range d.header {
+		if f := findTagField(all, h, d.options.FieldNameMatcher); f != nil {
+			fields[i] = f.index
+		}
+	}
+	d.fields[t] = fields
+	return fields
+}
+
+func (d *CSVDecoder) setCell(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	if v.Kind() == reflect.Ptr {
+		if s == "" {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.setCell(v.Elem(), s)
+	}
+
+	switch {
+	case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+		v.Set(reflect.ValueOf(s))
+		return nil
+	case v.Type() == timeType && s != "":
+		var err error
+		for _, layout := range d.options.TimeLayouts {
+			var t time.Time
+			if t, err = time.Parse(layout, s); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return err
+	case v.Kind() == reflect.Bool && s != "":
+		if containsFold(d.options.TrueValues, s) {
+			v.SetBool(true)
+			return nil
+		}
+		if containsFold(d.options.FalseValues, s) {
+			v.SetBool(false)
+			return nil
+		}
+	}
+	return setValueString(v, s, &ValuesOptions{TimeLayout: d.options.TimeLayouts[0]})
+}
+
+func containsFold(l []string, s string) bool {
+	for _, e := range l {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// csvStructFields lists fields of t which can be a CSV cell
+func csvStructFields(t reflect.Type, tagName string) []tagField {
+	var fields []tagField
+	for _, f := range structFields(t, tagName, isValuesLeafType) {
+		if isValuesLeafType(f.typ) || f.typ.Kind() == reflect.Interface {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// CSVEncoder writes structs or maps as CSV records with a header row
+type CSVEncoder struct {
+	w       *csv.Writer
+	options *CSVOptions
+	header  []string
+	fields  []tagField
+
+	// structType is the type of the first encoded struct, whose fields are cached in fields
+	structType reflect.Type
+}
+
+func NewCSVEncoder(w io.Writer, optFns ...func(options *CSVOptions)) *CSVEncoder {
+	options := newCSVOptions(optFns)
+	cw := csv.NewWriter(w)
+	cw.Comma = options.Comma
+	return &CSVEncoder{
+		w:       cw,
+		options: options,
+	}
+}
+
+// Encode writes v which is a struct, a map with string keys, or a slice of them
+// The header is written before the first record: names of fields in order for structs,
+// or sorted keys of the first map for maps
+func (e *CSVEncoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return fmt.Errorf("cannot encode nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			if err := e.encodeRecord(rv.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	} else if err := e.encodeRecord(rv); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *CSVEncoder) encodeRecord(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return fmt.Errorf("cannot encode nil")
+		}
+		v = v.Elem()
+	}
+
+	var record []string
+	switch v.Kind() {
+	case reflect.Struct:
+		if e.header == nil {
+			e.structType = v.Type()
+			e.fields = csvStructFields(v.Type(), e.options.TagName)
+			e.header = make([]string, len(e.fields))
+			for i, f := range e.fields {
+				e.header[i] = f.name
+			}
+			if err := e.w.Write(e.header); err != nil {
+				return err
+			}
+		}
+		if e.structType == nil {
+			return fmt.Errorf("cannot encode %v after maps", v.Type())
+		}
+		if v.Type() != e.structType {
+			return fmt.Errorf("cannot encode %v after %v", v.Type(), e.structType)
+		}
+		record = make([]string, len(e.fields))
+		for i, f := range e.fields {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// nil embedded pointer
+				continue
+			}
+			if record[i], err = e.formatCell(fv); err != nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot encode %v", v.Type())
+		}
+		if e.header == nil {
+			e.header = make([]string, 0, v.Len())
+			for _, k := range v.MapKeys() {
+				e.header = append(e.header, k.String())
+			}
+			sort.Strings(e.header)
+			if err := e.w.Write(e.header); err != nil {
+				return err
+			}
+		}
+		record = make([]string, len(e.header))
+		for i, h := range e.header {
+			ev := v.MapIndex(reflect.ValueOf(h).Convert(v.Type().Key()))
+			if !ev.IsValid() {
+				continue
+			}
+			var err error
+			if record[i], err = e.formatCell(ev); err != nil {
+				return fmt.Errorf("%s: %w", h, err)
+			}
+		}
+	default:
+		return fmt.Errorf("cannot encode %v", v.Type())
+	}
+	return e.w.Write(record)
+}
+
+func (e *CSVEncoder) formatCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Bool && len(e.options.TrueValues) > 0 && len(e.options.FalseValues) > 0 {
+		if v.Bool() {
+			return e.options.TrueValues[0], nil
+		}
+		return e.options.FalseValues[0], nil
+	}
+	if !isValuesLeafType(v.Type()) {
+		return "", fmt.Errorf("cannot encode %v as cell", v.Type())
+	}
+	return formatValueString(v, &ValuesOptions{TimeLayout: e.options.TimeLayouts[0]})
+}
@@ -0,0 +1,179 @@
+package conv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvTestBase struct {
+	ID int64 `csv:"id"`
+}
+
+type csvTestProduct struct {
+	csvTestBase
+	Name      string    `csv:"name"`
+	Price     float64   `csv:"price"`
+	InStock   bool      `csv:"in_stock"`
+	Released  time.Time `csv:"released"`
+	Discount  *float64  `csv:"discount"`
+	Note      string
+	Internal  string `csv:"-"`
+	Variants  []string
+	unexposed int
+}
+
+func TestCSVDecoder(t *testing.T) {
+	data := "\ufeffid,name,price,in_stock,released,discount,NOTE,unknown\n" +
+		"1,Pen,1.5,yes,2024-01-02,,fine,x\n" +
+		"2,\"Book, hard\",12,N,2024-01-03T04:05:06Z,0.1,,y\n"
+
+	t.Run("Struct", func(t *testing.T) {
+		dec := NewCSVDecoder(strings.NewReader(data), func(options *CSVOptions) {
+			options.TimeLayouts = []string{"2006-01-02", time.RFC3339}
+			options.TrueValues = []string{"yes", "y"}
+			options.FalseValues = []string{"no", "n"}
+		})
+
+		var products []csvTestProduct
+		for {
+			var p csvTestProduct
+			err := dec.Decode(&p)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			products = append(products, p)
+		}
+		if len(products) != 2 {
+			t.Fatal(products)
+		}
+		p := products[0]
+		if p.ID != 1 || p.Name != "Pen" || p.Price != 1.5 || !p.InStock || p.Discount != nil || p.Note != "fine" {
+			t.Fatalf("%#v", p)
+		}
+		if !p.Released.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Fatal(p.Released)
+		}
+		p = products[1]
+		if p.Name != "Book, hard" || p.InStock || p.Discount == nil || *p.Discount != 0.1 || p.Released.Hour() != 4 {
+			t.Fatalf("%#v", p)
+		}
+
+		header, _ := dec.Header()
+		if header[0] != "id" {
+			t.Fatal(header)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		dec := NewCSVDecoder(strings.NewReader("a;b\n1;x\n"), func(options *CSVOptions) {
+			options.Comma = ';'
+		})
+		var m map[string]string
+		if err := dec.Decode(&m); err != nil {
+			t.Fatal(err)
+		}
+		if m["a"] != "1" || m["b"] != "x" {
+			t.Fatal(m)
+		}
+		if err := dec.Decode(&m); err != io.EOF {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		dec := NewCSVDecoder(strings.NewReader("id,price\n1,2\n2,abc\n"))
+		var p csvTestProduct
+		if err := dec.Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		err := dec.Decode(&p)
+		var csvErr *CSVError
+		if !errors.As(err, &csvErr) {
+			t.Fatal(err)
+		}
+		if csvErr.Row != 3 || csvErr.Column != 2 || csvErr.Header != "price" || !errors.Is(err, strconv.ErrSyntax) {
+			t.Fatal(err)
+		}
+
+		dec = NewCSVDecoder(strings.NewReader("in_stock\nmaybe\n"))
+		if err = dec.Decode(&p); !errors.As(err, &csvErr) || csvErr.Row != 2 {
+			t.Fatal(err)
+		}
+		if err = dec.Decode(p); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
+
+func TestCSVEncoder(t *testing.T) {
+	discount := 0.25
+	products := []*csvTestProduct{
+		{
+			csvTestBase: csvTestBase{ID: 1},
+			Name:        "Book, hard",
+			Price:       12,
+			InStock:     true,
+			Released:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Discount:    &discount,
+			Internal:    "x",
+			Variants:    []string{"a"},
+		},
+		{csvTestBase: csvTestBase{ID: 2}, Name: "Pen", Price: 1.5},
+	}
+
+	var buf bytes.Buffer
+	enc := NewCSVEncoder(&buf, func(options *CSVOptions) {
+		options.TimeLayouts = []string{"2006-01-02"}
+		options.TrueValues = []string{"yes"}
+		options.FalseValues = []string{"no"}
+	})
+	if err := enc.Encode(products); err != nil {
+		t.Fatal(err)
+	}
+	expected := "id,name,price,in_stock,released,discount,Note\n" +
+		"1,\"Book, hard\",12,yes,2024-01-02,0.25,\n" +
+		"2,Pen,1.5,no,0001-01-01,,\n"
+	if buf.String() != expected {
+		t.Fatalf("expect %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	enc = NewCSVEncoder(&buf)
+	if err := enc.Encode(map[string]any{"b": 1, "a": "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode([]map[string]any{{"a": "y", "c": 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a,b\nx,1\ny,\n" {
+		t.Fatalf("%q", buf.String())
+	}
+	if err := enc.Encode(products[0]); err == nil {
+		t.Fatal("should fail")
+	}
+
+	type A struct{ X, Y, Z string }
+	type B struct{ X string }
+	buf.Reset()
+	enc = NewCSVEncoder(&buf)
+	if err := enc.Encode(A{"1", "2", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(&A{X: "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Encode(B{"1"}); err == nil {
+		t.Fatal("should fail")
+	}
+	if buf.String() != "X,Y,Z\n1,2,3\n4,,\n" {
+		t.Fatalf("%q", buf.String())
+	}
+}
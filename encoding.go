@@ -0,0 +1,278 @@
+package conv
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Encoding is a text encoding of byte slices
+type Encoding int
+
+const (
+	// EncodingRaw treats string as raw bytes
+	EncodingRaw Encoding = iota
+	// EncodingHex is lower case hexadecimal encoding, upper case is accepted while decoding
+	EncodingHex
+	// EncodingBase32 is the standard base32 encoding defined in RFC 4648
+	EncodingBase32
+	// EncodingBase32Raw is EncodingBase32 without padding
+	EncodingBase32Raw
+	// EncodingBase32Hex is the "Extended Hex Alphabet" base32 encoding defined in RFC 4648
+	EncodingBase32Hex
+	// EncodingBase32HexRaw is EncodingBase32Hex without padding
+	EncodingBase32HexRaw
+	// EncodingBase58 is the base58 encoding with Bitcoin alphabet
+	EncodingBase58
+	// EncodingBase64 is the standard base64 encoding defined in RFC 4648
+	EncodingBase64
+	// EncodingBase64Raw is EncodingBase64 without padding
+	EncodingBase64Raw
+	// EncodingBase64URL is the URL and file name safe base64 encoding defined in RFC 4648
+	EncodingBase64URL
+	// EncodingBase64URLRaw is EncodingBase64URL without padding
+	EncodingBase64URLRaw
+	// EncodingZ85 is the ZeroMQ base85 encoding, length of data must be a multiple of 4
+	EncodingZ85
+)
+
+var encodingNames = [...]string{
+	EncodingRaw:          "raw",
+	EncodingHex:          "hex",
+	EncodingBase32:       "base32",
+	EncodingBase32Raw:    "base32raw",
+	EncodingBase32Hex:    "base32hex",
+	EncodingBase32HexRaw: "base32hexraw",
+	EncodingBase58:       "base58",
+	EncodingBase64:       "base64",
+	EncodingBase64Raw:    "base64raw",
+	EncodingBase64URL:    "base64url",
+	EncodingBase64URLRaw: "base64urlraw",
+	EncodingZ85:          "z85",
+}
+
+func (e Encoding) String() string {
+	if e >= 0 && int(e) < len(encodingNames) {
+		return encodingNames[e]
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// EncodeBytes encodes b into string with enc
+func EncodeBytes(b []byte, enc Encoding) (string, error) {
+	switch enc {
+	case EncodingRaw:
+		return string(b), nil
+	case EncodingHex:
+		return hex.EncodeToString(b), nil
+	case EncodingBase32:
+		return base32.StdEncoding.EncodeToString(b), nil
+	case EncodingBase32Raw:
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+	case EncodingBase32Hex:
+		return base32.HexEncoding.EncodeToString(b), nil
+	case EncodingBase32HexRaw:
+		return base32.HexEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+	case EncodingBase58:
+		return encodeBase58(b), nil
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(b), nil
+	case EncodingBase64Raw:
+		return base64.RawStdEncoding.EncodeToString(b), nil
+	case EncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(b), nil
+	case EncodingBase64URLRaw:
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	case EncodingZ85:
+		return encodeZ85(b)
+	default:
+		return "", fmt.Errorf("unknown encoding: %v", enc)
+	}
+}
+
+// DecodeBytes decodes s with enc
+func DecodeBytes(s string, enc Encoding) ([]byte, error) {
+	switch enc {
+	case EncodingRaw:
+		return []byte(s), nil
+	case EncodingHex:
+		return hex.DecodeString(s)
+	case EncodingBase32:
+		return base32.StdEncoding.DecodeString(s)
+	case EncodingBase32Raw:
+		return base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	case EncodingBase32Hex:
+		return base32.HexEncoding.DecodeString(s)
+	case EncodingBase32HexRaw:
+		return base32.HexEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	case EncodingBase58:
+		return decodeBase58(s)
+	case EncodingBase64:
+		return base64.StdEncoding.DecodeString(s)
+	case EncodingBase64Raw:
+		return base64.RawStdEncoding.DecodeString(s)
+	case EncodingBase64URL:
+		return base64.URLEncoding.DecodeString(s)
+	case EncodingBase64URLRaw:
+		return base64.RawURLEncoding.DecodeString(s)
+	case EncodingZ85:
+		return decodeZ85(s)
+	default:
+		return nil, fmt.Errorf("unknown encoding: %v", enc)
+	}
+}
+
+// detectionOrder lists encodings from the most restrictive alphabet to the least restrictive one
+var detectionOrder = []Encoding{
+	EncodingHex,
+	EncodingBase32,
+	EncodingBase32Raw,
+	EncodingBase32Hex,
+	EncodingBase32HexRaw,
+	EncodingBase58,
+	EncodingBase64,
+	EncodingBase64URL,
+	EncodingBase64Raw,
+	EncodingBase64URLRaw,
+	EncodingZ85,
+}
+
+// DetectEncoding guesses the encoding of s
+// It returns the first encoding which can decode s, trying from the most restrictive alphabet:
+// hex, base32, base32hex, base58, base64, base64url and z85. Padded variants are tried before raw variants.
+// As alphabets overlap, the result is a guess, e.g. "cafe" is reported as hex although it is valid base64 too.
+func DetectEncoding(s string) (Encoding, bool) {
+	if s == "" {
+		return EncodingRaw, false
+	}
+
+	for _, enc := range detectionOrder {
+		if _, err := DecodeBytes(s, enc); err == nil {
+			return enc, true
+		}
+	}
+	return EncodingRaw, false
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Indexes = makeAlphabetIndexes(base58Alphabet)
+
+func encodeBase58(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) is less than 1.38
+	size := (len(b)-zeros)*138/100 + 1
+	buf := make([]byte, size)
+	length := 0
+	for _, c := range b[zeros:] {
+		carry := int(c)
+		i := 0
+		for j := size - 1; (carry != 0 || i < length) && j >= 0; j-- {
+			carry += 256 * int(buf[j])
+			buf[j] = byte(carry % 58)
+			carry /= 58
+			i++
+		}
+		length = i
+	}
+
+	res := make([]byte, zeros+length)
+	for i := 0; i < zeros; i++ {
+		res[i] = base58Alphabet[0]
+	}
+	for i, d := range buf[size-length:] {
+		res[zeros+i] = base58Alphabet[d]
+	}
+	return string(res)
+}
+
+func decodeBase58(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) is less than 0.733
+	size := (len(s)-zeros)*733/1000 + 1
+	buf := make([]byte, size)
+	length := 0
+	for k := zeros; k < len(s); k++ {
+		carry := int(base58Indexes[s[k]])
+		if carry < 0 {
+			return nil, fmt.Errorf("illegal base58 data at input byte %d", k)
+		}
+		i := 0
+		for j := size - 1; (carry != 0 || i < length) && j >= 0; j-- {
+			carry += 58 * int(buf[j])
+			buf[j] = byte(carry % 256)
+			carry /= 256
+			i++
+		}
+		length = i
+	}
+
+	res := make([]byte, zeros+length)
+	copy(res[zeros:], buf[size-length:])
+	return res, nil
+}
+
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+var z85Indexes = makeAlphabetIndexes(z85Alphabet)
+
+func encodeZ85(b []byte) (string, error) {
+	if len(b)%4 != 0 {
+		return "", errors.New("z85: length of data must be a multiple of 4")
+	}
+
+	res := make([]byte, len(b)/4*5)
+	for i, j := 0, 0; i < len(b); i, j = i+4, j+5 {
+		v := uint32(b[i])<<24 | uint32(b[i+1])<<16 | uint32(b[i+2])<<8 | uint32(b[i+3])
+		for k := 4; k >= 0; k-- {
+			res[j+k] = z85Alphabet[v%85]
+			v /= 85
+		}
+	}
+	return string(res), nil
+}
+
+func decodeZ85(s string) ([]byte, error) {
+	if len(s)%5 != 0 {
+		return nil, errors.New("z85: length of text must be a multiple of 5")
+	}
+
+	res := make([]byte, len(s)/5*4)
+	for i, j := 0, 0; i < len(s); i, j = i+5, j+4 {
+		var v uint64
+		for k := 0; k < 5; k++ {
+			d := z85Indexes[s[i+k]]
+			if d < 0 {
+				return nil, fmt.Errorf("illegal z85 data at input byte %d", i+k)
+			}
+			v = v*85 + uint64(d)
+		}
+		if v > math.MaxUint32 {
+			return nil, fmt.Errorf("illegal z85 data at input byte %d", i)
+		}
+		res[j], res[j+1], res[j+2], res[j+3] = byte(v>>24), byte(v>>16), byte(v>>8), byte(v)
+	}
+	return res, nil
+}
+
+func makeAlphabetIndexes(alphabet string) [256]int8 {
+	var indexes [256]int8
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		indexes[alphabet[i]] = int8(i)
+	}
+	return indexes
+}
@@ -0,0 +1,138 @@
+package conv
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestEncodeBytes(t *testing.T) {
+	cases := []struct {
+		Data     []byte
+		Encoding Encoding
+		Result   string
+	}{
+		{[]byte("hello"), EncodingRaw, "hello"},
+		{[]byte{0xde, 0xad, 0xbe, 0xef}, EncodingHex, "deadbeef"},
+		{[]byte("foobar"), EncodingBase32, "MZXW6YTBOI======"},
+		{[]byte("foobar"), EncodingBase32Raw, "MZXW6YTBOI"},
+		{[]byte("foobar"), EncodingBase32Hex, "CPNMUOJ1E8======"},
+		{[]byte("foobar"), EncodingBase32HexRaw, "CPNMUOJ1E8"},
+		{[]byte("Hello World!"), EncodingBase58, "2NEpo7TZRRrLZSi2U"},
+		{[]byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, EncodingBase58, "11233QC4"},
+		{[]byte{0xfb, 0xff}, EncodingBase64, "+/8="},
+		{[]byte{0xfb, 0xff}, EncodingBase64Raw, "+/8"},
+		{[]byte{0xfb, 0xff}, EncodingBase64URL, "-_8="},
+		{[]byte{0xfb, 0xff}, EncodingBase64URLRaw, "-_8"},
+		{[]byte{0x86, 0x4f, 0xd2, 0x6f, 0xb5, 0x59, 0xf7, 0x5b}, EncodingZ85, "HelloWorld"},
+	}
+
+	for _, c := range cases {
+		s, err := EncodeBytes(c.Data, c.Encoding)
+		if err != nil {
+			t.Fatal(c.Encoding, err)
+		}
+		if s != c.Result {
+			t.Errorf("%v: expect %s, got %s", c.Encoding, c.Result, s)
+		}
+
+		b, err := DecodeBytes(s, c.Encoding)
+		if err != nil {
+			t.Fatal(c.Encoding, err)
+		}
+		if !bytes.Equal(b, c.Data) {
+			t.Errorf("%v: expect %x, got %x", c.Encoding, c.Data, b)
+		}
+	}
+}
+
+func TestEncodeBytesRoundTrip(t *testing.T) {
+	for enc := EncodingRaw; enc <= EncodingZ85; enc++ {
+		for _, n := range []int{0, 4, 32, 64} {
+			data := make([]byte, n)
+			rand.Read(data)
+			if n > 0 {
+				data[0] = 0
+			}
+			s, err := EncodeBytes(data, enc)
+			if err != nil {
+				t.Fatal(enc, err)
+			}
+			b, err := DecodeBytes(s, enc)
+			if err != nil {
+				t.Fatal(enc, err)
+			}
+			if !bytes.Equal(b, data) {
+				t.Fatalf("%v: expect %x, got %x", enc, data, b)
+			}
+		}
+	}
+}
+
+func TestDecodeBytesBad(t *testing.T) {
+	cases := []struct {
+		Text     string
+		Encoding Encoding
+	}{
+		{"abc", EncodingHex},
+		{"0OIl", EncodingBase58},
+		{"Hello", EncodingZ85 + 1},
+		{"Hell", EncodingZ85},
+		{"#####", EncodingZ85},
+	}
+
+	for _, c := range cases {
+		if _, err := DecodeBytes(c.Text, c.Encoding); err == nil {
+			t.Errorf("%v %q: should fail", c.Encoding, c.Text)
+		}
+	}
+
+	if _, err := EncodeBytes([]byte{1, 2, 3}, EncodingZ85); err == nil {
+		t.Error("should fail")
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	cases := []struct {
+		Text     string
+		Encoding Encoding
+	}{
+		{"deadbeef", EncodingHex},
+		{"MZXW6YTBOI======", EncodingBase32},
+		{"2NEpo7TZRRrLZSi2U", EncodingBase58},
+		{"+/8=", EncodingBase64},
+		{"-_8=", EncodingBase64URL},
+		{"+/8", EncodingBase64Raw},
+		{"-_8", EncodingBase64URLRaw},
+		{"Hello.World", EncodingRaw},
+		{"Hello.Worl", EncodingZ85},
+	}
+
+	for _, c := range cases {
+		enc, ok := DetectEncoding(c.Text)
+		if ok != (c.Encoding != EncodingRaw) || enc != c.Encoding {
+			t.Errorf("%q: expect %v, got %v", c.Text, c.Encoding, enc)
+		}
+	}
+}
+
+func TestUnsafeAssignEncodedBytes(t *testing.T) {
+	type Token struct {
+		ID    [4]byte
+		Value []byte
+	}
+
+	var token Token
+	err := UnsafeAssign(&token, map[string]any{"ID": "deadbeef", "Value": "cafe"}, func(options *UnsafeAssignOptions) {
+		options.BytesEncoding = EncodingHex
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != [4]byte{0xde, 0xad, 0xbe, 0xef} {
+		t.Fatalf("%x", token.ID)
+	}
+	if diff := diffSlice([]byte{0xca, 0xfe}, token.Value); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,194 @@
+package conv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// EnvOptions controls how environment variables are bound to struct fields
+type EnvOptions struct {
+	// Prefix is prepended to all names, e.g. APP for APP_PORT
+	Prefix string
+
+	// Separator splits values of slices and entries of maps, "," by default
+	Separator string
+
+	// Lookup returns the value of an environment variable, os.LookupEnv by default
+	Lookup func(name string) (string, bool)
+}
+
+// MissingEnvError reports required environment variables which are not set
+type MissingEnvError struct {
+	Names []string
+}
+
+func (e *MissingEnvError) Error() string {
+	return "missing required environment variables: " + strings.Join(e.Names, ", ")
+}
+
+// LoadEnv fills fields of struct pointed by dst with environment variables
+// A field is bound to the snake-case upper name of the field, e.g. DBHost to DB_HOST, or to the name in tag `env:"NAME"`.
+// Fields of nested structs are prefixed with the name of the parent field, fields of embedded structs are not.
+// Tag `env:"NAME,required"` marks a variable as required, tag `default:"value"` is used if a variable is unset.
+// Empty values are treated as unset.
+// Slices are parsed from separated values, e.g. a,b,c, and maps from separated pairs, e.g. k1=v1,k2=v2.
+// All missing required variables are reported at once in MissingEnvError, joined with parsing errors if any
+func LoadEnv(dst any, optFns ...func(options *EnvOptions)) error {
+	options := &EnvOptions{
+		Separator: ",",
+		Lookup:    os.LookupEnv,
+	}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot load env into %T", dst)
+	}
+
+	prefix := options.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+
+	l := &envLoader{options: options}
+	l.loadStruct(dv.Elem(), prefix)
+
+	var errs []error
+	if len(l.missing) > 0 {
+		errs = append(errs, &MissingEnvError{Names: l.missing})
+	}
+	return errors.Join(append(errs, l.errs...)...)
+}
+
+type envLoader struct {
+	options *EnvOptions
+	missing []string
+	errs    []error
+}
+
+func (l *envLoader) loadStruct(v reflect.Value, prefix string) {
+	for _, f := range structFields(v.Type(), "env", isValuesLeafType) {
+		fv, err := fieldByIndexAlloc(v, f.index)
+		if err != nil {
+			// nil embedded pointer to an unexported struct
+			continue
+		}
+
+		name := f.name
+		if !f.tagged {
+			name = toScreamingSnakeCase(name)
+		}
+		name = prefix + name
+
+		ft := f.typ
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !isValuesLeafType(ft) {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(ft))
+				}
+				fv = fv.Elem()
+			}
+			l.loadStruct(fv, name+"_")
+			continue
+		}
+
+		value, ok := l.options.Lookup(name)
+		if !ok || value == "" {
+			value, ok = f.tag.Lookup("default")
+		}
+		if !ok || value == "" {
+			if hasTagOption(f.opts, "required") {
+				l.missing = append(l.missing, name)
+			}
+			continue
+		}
+
+		if err = l.set(fv, value); err != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
+
+func (l *envLoader) set(v reflect.Value, s string) error {
+	valuesOptions := &ValuesOptions{TimeLayout: time.RFC3339}
+	if isValuesLeafType(v.Type()) {
+		return setValueString(v, s, valuesOptions)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return l.set(v.Elem(), s)
+	case reflect.Slice:
+		items := l.split(s)
+		sv := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValueString(sv.Index(i), item, valuesOptions); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(sv)
+		return nil
+	case reflect.Map:
+		mv := reflect.MakeMap(v.Type())
+		for _, item := range l.split(s) {
+			key, value, found := strings.Cut(item, "=")
+			if !found {
+				return fmt.Errorf("invalid map entry %q", item)
+			}
+			kv := reflect.New(v.Type().Key()).Elem()
+			if err := setValueString(kv, strings.TrimSpace(key), valuesOptions); err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := setValueString(ev, strings.TrimSpace(value), valuesOptions); err != nil {
+				return fmt.Errorf("[%s]: %w", key, err)
+			}
+			mv.SetMapIndex(kv, ev)
+		}
+		v.Set(mv)
+		return nil
+	default:
+		return fmt.Errorf("cannot parse env into %v", v.Type())
+	}
+}
+
+func (l *envLoader) split(s string) []string {
+	items := strings.Split(s, l.options.Separator)
+	res := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// toScreamingSnakeCase converts a field name to an environment variable name, e.g. HTTPPort to HTTP_PORT
+func toScreamingSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package conv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToScreamingSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"Port":      "PORT",
+		"DBHost":    "DB_HOST",
+		"HTTPPort":  "HTTP_PORT",
+		"UserID":    "USER_ID",
+		"Retry2Max": "RETRY2_MAX",
+		"already_x": "ALREADY_X",
+	}
+	for s, expected := range cases {
+		if got := toScreamingSnakeCase(s); got != expected {
+			t.Errorf("%s: expect %s, got %s", s, expected, got)
+		}
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	type DB struct {
+		Host     string `env:",required"`
+		Port     int    `default:"5432"`
+		Password string `env:"PASS,required"`
+	}
+
+	type Common struct {
+		Debug bool
+	}
+
+	type Config struct {
+		Common
+		Name      string            `env:"SERVICE_NAME"`
+		HTTPPort  uint16            `default:"8080"`
+		Timeout   time.Duration     `default:"5s"`
+		Hosts     []string          `env:"HOSTS"`
+		Weights   map[string]int    `env:"WEIGHTS"`
+		Labels    map[string]string `env:"LABELS,required"`
+		Primary   DB
+		Replica   *DB    `env:"RO"`
+		Ignored   string `env:"-"`
+		unexposed string
+	}
+
+	lookup := func(env map[string]string) func(string) (string, bool) {
+		return func(name string) (string, bool) {
+			v, ok := env[name]
+			return v, ok
+		}
+	}
+
+	t.Run("Good", func(t *testing.T) {
+		env := map[string]string{
+			"APP_DEBUG":            "true",
+			"APP_SERVICE_NAME":     "api",
+			"APP_HTTP_PORT":        "",
+			"APP_HOSTS":            "a.local, b.local,",
+			"APP_WEIGHTS":          "a=1,b=2",
+			"APP_LABELS":           "team=core",
+			"APP_PRIMARY_HOST":     "db1",
+			"APP_PRIMARY_PASS":     "secret",
+			"APP_RO_HOST":          "db2",
+			"APP_RO_PORT":          "6432",
+			"APP_RO_PASS":          "secret2",
+			"APP_IGNORED":          "x",
+			"APP_TIMEOUT":          "1m",
+			"APP_PRIMARY_PASSWORD": "wrong",
+		}
+		var c Config
+		err := LoadEnv(&c, func(options *EnvOptions) {
+			options.Prefix = "APP"
+			options.Lookup = lookup(env)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.Debug || c.Name != "api" || c.HTTPPort != 8080 || c.Timeout != time.Minute || c.Ignored != "" {
+			t.Fatalf("%#v", c)
+		}
+		if diff := diffSlice([]string{"a.local", "b.local"}, c.Hosts); diff != "" {
+			t.Fatal(diff)
+		}
+		if c.Weights["a"] != 1 || c.Weights["b"] != 2 || c.Labels["team"] != "core" {
+			t.Fatal(c.Weights, c.Labels)
+		}
+		if c.Primary.Host != "db1" || c.Primary.Port != 5432 || c.Primary.Password != "secret" {
+			t.Fatal(c.Primary)
+		}
+		if c.Replica == nil || c.Replica.Host != "db2" || c.Replica.Port != 6432 {
+			t.Fatal(c.Replica)
+		}
+	})
+
+	t.Run("Separator", func(t *testing.T) {
+		var c struct {
+			Hosts []string
+		}
+		err := LoadEnv(&c, func(options *EnvOptions) {
+			options.Separator = ";"
+			options.Lookup = lookup(map[string]string{"HOSTS": "a,b;c"})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := diffSlice([]string{"a,b", "c"}, c.Hosts); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		env := map[string]string{
+			"HTTP_PORT":    "70000",
+			"WEIGHTS":      "a=1,b",
+			"PRIMARY_HOST": "db1",
+		}
+		var c Config
+		err := LoadEnv(&c, func(options *EnvOptions) {
+			options.Lookup = lookup(env)
+		})
+		var missing *MissingEnvError
+		if !errors.As(err, &missing) {
+			t.Fatal(err)
+		}
+		expected := []string{"LABELS", "PRIMARY_PASS", "RO_HOST", "RO_PASS"}
+		if diff := diffSlice(expected, missing.Names); diff != "" {
+			t.Fatal(diff)
+		}
+		for _, s := range []string{"HTTP_PORT:", "WEIGHTS:"} {
+			if !strings.Contains(err.Error(), s) {
+				t.Errorf("%s is not reported: %v", s, err)
+			}
+		}
+
+		if err = LoadEnv(c); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package conv
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FlagOptions controls how struct fields are bound to command-line flags
+type FlagOptions struct {
+	// Prefix is prepended to all flag names, e.g. server for -server-port
+	Prefix string
+
+	// TimeLayout is used to parse and format time.Time, time.RFC3339 by default
+	TimeLayout string
+}
+
+// BindFlags registers a flag for each field of struct pointed by dst
+// A flag is named by the kebab-case name of the field, e.g. HTTPPort as http-port, or by tag `flag:"name"`.
+// Usage is read from tag `usage:"..."`, and the default is the current field value.
+// Fields of nested structs are prefixed with the name of the parent field, fields of embedded structs are not.
+// Slice fields are repeatable flags, and map fields are repeatable flags of key=value.
+// Bool fields can be set without value, e.g. -verbose
+func BindFlags(fs *flag.FlagSet, dst any, optFns ...func(options *FlagOptions)) error {
+	options := &FlagOptions{
+		TimeLayout: time.RFC3339,
+	}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind flags to %T", dst)
+	}
+
+	prefix := options.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "-") {
+		prefix += "-"
+	}
+	return bindStructFlags(fs, dv.Elem(), prefix, options)
+}
+
+func bindStructFlags(fs *flag.FlagSet, v reflect.Value, prefix string, options *FlagOptions) error {
+	for _, f := range structFields(v.Type(), "flag", isValuesLeafType) {
+		fv, err := fieldByIndexAlloc(v, f.index)
+		if err != nil {
+			// nil embedded pointer to an unexported struct
+			continue
+		}
+
+		name := f.name
+		if !f.tagged {
+			name = toKebabCase(name)
+		}
+		name = prefix + name
+
+		ft := f.typ
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !isValuesLeafType(ft) {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(ft))
+				}
+				fv = fv.Elem()
+			}
+			if err = bindStructFlags(fs, fv, name+"-", options); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !isFlagType(f.typ) {
+			return fmt.Errorf("cannot bind flag %s to %v", name, f.typ)
+		}
+		if fs.Lookup(name) != nil {
+			return fmt.Errorf("flag redefined: %s", name)
+		}
+		fs.Var(&fieldFlag{v: fv, options: options}, name, f.tag.Get("usage"))
+	}
+	return nil
+}
+
+func isFlagType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return isValuesLeafType(t) || isValuesLeafType(t.Elem())
+	case reflect.Map:
+		return isValuesLeafType(t.Key()) && isValuesLeafType(t.Elem())
+	}
+	return isValuesLeafType(t)
+}
+
+// toKebabCase converts a field name to a flag name, e.g. HTTPPort to http-port
+func toKebabCase(s string) string {
+	return strings.ToLower(strings.ReplaceAll(toScreamingSnakeCase(s), "_", "-"))
+}
+
+// fieldFlag is a flag.Value bound to a struct field
+type fieldFlag struct {
+	v       reflect.Value
+	options *FlagOptions
+	// set is false until the flag is parsed, so that the first value replaces the default slice or map
+	set bool
+}
+
+var _ flag.Value = (*fieldFlag)(nil)
+
+func (f *fieldFlag) String() string {
+	// flag package calls String on a zero fieldFlag to check default values
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	v := f.v
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	valuesOptions := &ValuesOptions{TimeLayout: f.options.TimeLayout}
+	switch {
+	case isValuesLeafType(v.Type()):
+		s, _ := formatValueString(v, valuesOptions)
+		return s
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i], _ = formatValueString(reflect.Indirect(v.Index(i)), valuesOptions)
+		}
+		return strings.Join(items, ",")
+	case v.Kind() == reflect.Map:
+		items := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, _ := formatValueString(iter.Key(), valuesOptions)
+			e, _ := formatValueString(reflect.Indirect(iter.Value()), valuesOptions)
+			items = append(items, k+"="+e)
+		}
+		// map iteration order is random
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	}
+	return ""
+}
+
+func (f *fieldFlag) Set(s string) error {
+	v := f.v
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	valuesOptions := &ValuesOptions{TimeLayout: f.options.TimeLayout}
+	reset := !f.set
+	f.set = true
+	switch {
+	case isValuesLeafType(v.Type()):
+		if s == "" && v.Kind() != reflect.String {
+			return fmt.Errorf("empty value")
+		}
+		return setValueString(v, s, valuesOptions)
+	case v.Kind() == reflect.Slice:
+		if reset {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 1))
+		}
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err := setValueString(ev, s, valuesOptions); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, ev))
+		return nil
+	case v.Kind() == reflect.Map:
+		key, value, found := strings.Cut(s, "=")
+		if !found {
+			return fmt.Errorf("expect key=value")
+		}
+		if reset || v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		kv := reflect.New(v.Type().Key()).Elem()
+		if err := setValueString(kv, key, valuesOptions); err != nil {
+			return fmt.Errorf("key: %w", err)
+		}
+		ev := reflect.New(v.Type().Elem()).Elem()
+		if err := setValueString(ev, value, valuesOptions); err != nil {
+			return err
+		}
+		v.SetMapIndex(kv, ev)
+		return nil
+	}
+	return fmt.Errorf("cannot set %v", v.Type())
+}
+
+// IsBoolFlag allows bool flags to be set without value
+func (f *fieldFlag) IsBoolFlag() bool {
+	if !f.v.IsValid() {
+		return false
+	}
+	t := f.v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Bool
+}
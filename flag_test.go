@@ -0,0 +1,120 @@
+package conv
+
+import (
+	"bytes"
+	"flag"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindFlags(t *testing.T) {
+	type TLS struct {
+		Cert string `usage:"certificate file"`
+		Key  string
+	}
+
+	type Common struct {
+		Verbose bool `flag:"v" usage:"verbose output"`
+	}
+
+	type Config struct {
+		Common
+		HTTPPort int               `usage:"port to listen"`
+		Timeout  time.Duration     `flag:"timeout"`
+		Hosts    []string          `flag:"host"`
+		Labels   map[string]string `flag:"label"`
+		IP       net.IP
+		Limit    *int
+		Debug    *bool
+		TLS      TLS
+		Admin    *TLS
+		Ignored  string `flag:"-"`
+		internal string
+	}
+
+	t.Run("Good", func(t *testing.T) {
+		c := Config{HTTPPort: 8080, Timeout: time.Second, Hosts: []string{"default"}}
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		if err := BindFlags(fs, &c); err != nil {
+			t.Fatal(err)
+		}
+		if f := fs.Lookup("http-port"); f == nil || f.DefValue != "8080" || f.Usage != "port to listen" {
+			t.Fatal(f)
+		}
+		if f := fs.Lookup("host"); f == nil || f.DefValue != "default" {
+			t.Fatal(f)
+		}
+		if fs.Lookup("ignored") != nil || fs.Lookup("internal") != nil {
+			t.Fatal("should be skipped")
+		}
+
+		args := []string{"-v", "-http-port=9090", "-timeout", "1m", "-host", "a", "-host=b",
+			"-label", "env=prod", "-label", "team=core", "-ip", "10.0.0.1", "-limit", "5", "-debug",
+			"-tls-cert", "cert.pem", "-admin-key", "admin.key", "rest"}
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		if !c.Verbose || c.HTTPPort != 9090 || c.Timeout != time.Minute || c.IP.String() != "10.0.0.1" {
+			t.Fatalf("%#v", c)
+		}
+		if diff := diffSlice([]string{"a", "b"}, c.Hosts); diff != "" {
+			t.Fatal(diff)
+		}
+		if c.Labels["env"] != "prod" || c.Labels["team"] != "core" {
+			t.Fatal(c.Labels)
+		}
+		if c.Limit == nil || *c.Limit != 5 || c.Debug == nil || !*c.Debug {
+			t.Fatal(c.Limit, c.Debug)
+		}
+		if c.TLS.Cert != "cert.pem" || c.Admin == nil || c.Admin.Key != "admin.key" {
+			t.Fatal(c.TLS, c.Admin)
+		}
+		if fs.Arg(0) != "rest" {
+			t.Fatal(fs.Args())
+		}
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		var c Config
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		var buf bytes.Buffer
+		fs.SetOutput(&buf)
+		if err := BindFlags(fs, &c, func(options *FlagOptions) {
+			options.Prefix = "app"
+		}); err != nil {
+			t.Fatal(err)
+		}
+		fs.PrintDefaults()
+		for _, s := range []string{"-app-v", "verbose output", "-app-tls-cert", "certificate file"} {
+			if !strings.Contains(buf.String(), s) {
+				t.Errorf("%s is missing in %s", s, buf.String())
+			}
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		var c Config
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		if err := BindFlags(fs, &c); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"-http-port=x"}, {"-label=x"}, {"-timeout=5"}, {"-limit="}} {
+			if err := fs.Parse(args); err == nil {
+				t.Errorf("%v: should fail", args)
+			}
+		}
+
+		if err := BindFlags(fs, &c); err == nil {
+			t.Fatal("should fail for redefined flags")
+		}
+		if err := BindFlags(flag.NewFlagSet("test", flag.ContinueOnError), &struct{ C chan int }{}); err == nil {
+			t.Fatal("should fail")
+		}
+		if err := BindFlags(flag.NewFlagSet("test", flag.ContinueOnError), c); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
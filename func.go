@@ -1,5 +1,11 @@
 package conv
 
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
 type FuncE[S any, T any] func(S, error) (T, error)
 
 func ToFuncE[S any, T any](fn func(S) T) FuncE[S, T] {
@@ -11,3 +17,151 @@ func ToFuncE[S any, T any](fn func(S) T) FuncE[S, T] {
 		return fn(s), nil
 	}
 }
+
+// Then composes f and g into a FuncE which calls g with the result of f
+// g is not called if f fails
+func Then[A any, B any, C any](f FuncE[A, B], g FuncE[B, C]) FuncE[A, C] {
+	return func(a A, err error) (C, error) {
+		b, err := f(a, err)
+		if err != nil {
+			var c C
+			return c, err
+		}
+		return g(b, nil)
+	}
+}
+
+// Chain composes steps into a FuncE which calls them in order, it stops at the first failed step
+func Chain[T any](steps ...FuncE[T, T]) FuncE[T, T] {
+	return func(t T, err error) (T, error) {
+		if err != nil {
+			return t, err
+		}
+		for _, step := range steps {
+			if t, err = step(t, nil); err != nil {
+				return t, err
+			}
+		}
+		return t, nil
+	}
+}
+
+// MapE lifts f to a FuncE over slices, it stops at the first failed element and returns the error as *IndexError
+func MapE[S any, T any](f FuncE[S, T]) FuncE[[]S, []T] {
+	return func(a []S, err error) ([]T, error) {
+		if err != nil {
+			return nil, err
+		}
+		res := make([]T, len(a))
+		for i, s := range a {
+			if res[i], err = f(s, nil); err != nil {
+				return nil, &IndexError{Index: i, Err: err}
+			}
+		}
+		return res, nil
+	}
+}
+
+// RetryPolicy controls how Retry calls a FuncE again after it fails
+type RetryPolicy struct {
+	// MaxAttempts is the max number of calls including the first one, 1 if not positive
+	MaxAttempts int
+
+	// Delay is the wait time before the first retry
+	Delay time.Duration
+
+	// Multiplier grows the wait time after each retry, 1 if less than 1
+	Multiplier float64
+
+	// MaxDelay limits the wait time if positive
+	MaxDelay time.Duration
+
+	// Retryable reports whether err is worth retrying, all errors are retried if it's nil
+	Retryable func(err error) bool
+}
+
+// Retry calls f again according to policy until it succeeds, and returns the last error if all attempts fail
+// The incoming error is returned without calling f
+func Retry[S any, T any](f FuncE[S, T], policy RetryPolicy) FuncE[S, T] {
+	return func(s S, err error) (T, error) {
+		if err != nil {
+			var t T
+			return t, err
+		}
+		delay := policy.Delay
+		for attempt := 1; ; attempt++ {
+			t, err := f(s, nil)
+			if err == nil || attempt >= policy.MaxAttempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+				return t, err
+			}
+			if delay > 0 {
+				time.Sleep(delay)
+			}
+			if policy.Multiplier > 1 {
+				delay = time.Duration(float64(delay) * policy.Multiplier)
+			}
+			if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+				delay = policy.MaxDelay
+			}
+		}
+	}
+}
+
+// WithTimeout makes f fail with context.DeadlineExceeded if it doesn't return within timeout
+// f keeps running in background after timeout, and its result is discarded
+func WithTimeout[S any, T any](f FuncE[S, T], timeout time.Duration) FuncE[S, T] {
+	return func(s S, err error) (T, error) {
+		var t T
+		if err != nil {
+			return t, err
+		}
+		type result struct {
+			t   T
+			err error
+		}
+		ch := make(chan result, 1)
+		go func() {
+			var r result
+			r.t, r.err = f(s, nil)
+			ch <- r
+		}()
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case r := <-ch:
+			return r.t, r.err
+		case <-timer.C:
+			return t, fmt.Errorf("timeout after %v: %w", timeout, context.DeadlineExceeded)
+		}
+	}
+}
+
+// StepError is an error of the step named Name
+type StepError struct {
+	Name string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Named names f so that its errors are returned as *StepError
+// The incoming error is returned as is
+func Named[S any, T any](name string, f FuncE[S, T]) FuncE[S, T] {
+	return func(s S, err error) (T, error) {
+		if err != nil {
+			var t T
+			return t, err
+		}
+		t, err := f(s, nil)
+		if err != nil {
+			return t, &StepError{Name: name, Err: err}
+		}
+		return t, nil
+	}
+}
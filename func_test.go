@@ -0,0 +1,121 @@
+package conv
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	parse := Named("parse", FuncE[string, int](func(s string, err error) (int, error) {
+		if err != nil {
+			return 0, err
+		}
+		return strconv.Atoi(s)
+	}))
+	validate := Named("validate", FuncE[int, int](func(n int, err error) (int, error) {
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		return n, nil
+	}))
+	double := ToFuncE(func(n int) int { return n * 2 })
+	format := ToFuncE(strconv.Itoa)
+
+	pipeline := Then(Then(parse, Chain(validate, double, double)), format)
+
+	t.Run("Good", func(t *testing.T) {
+		s, err := pipeline("3", nil)
+		if err != nil || s != "12" {
+			t.Fatal(s, err)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		_, err := pipeline("x", nil)
+		var stepErr *StepError
+		if !errors.As(err, &stepErr) || stepErr.Name != "parse" || !errors.Is(err, strconv.ErrSyntax) {
+			t.Fatal(err)
+		}
+
+		_, err = pipeline("-1", nil)
+		if !errors.As(err, &stepErr) || stepErr.Name != "validate" || err.Error() != "validate: negative" {
+			t.Fatal(err)
+		}
+
+		errIn := errors.New("in")
+		if _, err = pipeline("1", errIn); err != errIn {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestMapE(t *testing.T) {
+	f := MapE(FuncE[string, int](func(s string, err error) (int, error) {
+		return strconv.Atoi(s)
+	}))
+	a, err := f([]string{"1", "2"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]int{1, 2}, a); diff != "" {
+		t.Fatal(diff)
+	}
+
+	_, err = f([]string{"1", "b"}, nil)
+	var indexErr *IndexError
+	if !errors.As(err, &indexErr) || indexErr.Index != 1 {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	errTemporary := errors.New("temporary")
+	var calls int
+	f := FuncE[string, string](func(s string, err error) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errTemporary
+		}
+		return strings.ToUpper(s), nil
+	})
+
+	s, err := Retry(f, RetryPolicy{MaxAttempts: 3, Delay: time.Millisecond, Multiplier: 2})("a", nil)
+	if err != nil || s != "A" || calls != 3 {
+		t.Fatal(s, err, calls)
+	}
+
+	calls = 0
+	_, err = Retry(f, RetryPolicy{MaxAttempts: 2})("a", nil)
+	if !errors.Is(err, errTemporary) || calls != 2 {
+		t.Fatal(err, calls)
+	}
+
+	calls = 0
+	_, err = Retry(f, RetryPolicy{MaxAttempts: 5, Retryable: func(err error) bool {
+		return !errors.Is(err, errTemporary)
+	}})("a", nil)
+	if !errors.Is(err, errTemporary) || calls != 1 {
+		t.Fatal(err, calls)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	f := ToFuncE(func(d time.Duration) string {
+		time.Sleep(d)
+		return d.String()
+	})
+
+	s, err := WithTimeout(f, time.Second)(time.Millisecond, nil)
+	if err != nil || s != "1ms" {
+		t.Fatal(s, err)
+	}
+
+	_, err = WithTimeout(f, time.Millisecond)(100*time.Millisecond, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
@@ -1,3 +1,3 @@
 module go.olapie.com/conv
 
-go 1.20
+go 1.21
@@ -0,0 +1,86 @@
+package conv
+
+import "container/heap"
+
+// Heap is a typed priority queue built on container/heap
+// The element for which less reports true against all others is popped first
+type Heap[E any] struct {
+	items heapItems[E]
+}
+
+// NewHeap creates a heap of elements in a, a is not modified
+func NewHeap[E any](a []E, less func(a, b E) bool) *Heap[E] {
+	h := &Heap[E]{
+		items: heapItems[E]{
+			elems: make([]E, len(a)),
+			less:  less,
+		},
+	}
+	copy(h.items.elems, a)
+	heap.Init(&h.items)
+	return h
+}
+
+func (h *Heap[E]) Push(e E) {
+	heap.Push(&h.items, e)
+}
+
+// Pop removes and returns the least element, or false if h is empty
+func (h *Heap[E]) Pop() (E, bool) {
+	if len(h.items.elems) == 0 {
+		var zero E
+		return zero, false
+	}
+	return heap.Pop(&h.items).(E), true
+}
+
+// Peek returns the least element without removing it, or false if h is empty
+func (h *Heap[E]) Peek() (E, bool) {
+	if len(h.items.elems) == 0 {
+		var zero E
+		return zero, false
+	}
+	return h.items.elems[0], true
+}
+
+func (h *Heap[E]) Len() int {
+	return len(h.items.elems)
+}
+
+// Slice returns elements in heap order, which is not sorted
+func (h *Heap[E]) Slice() []E {
+	a := make([]E, len(h.items.elems))
+	copy(a, h.items.elems)
+	return a
+}
+
+// heapItems implements heap.Interface
+type heapItems[E any] struct {
+	elems []E
+	less  func(a, b E) bool
+}
+
+func (h *heapItems[E]) Len() int {
+	return len(h.elems)
+}
+
+func (h *heapItems[E]) Less(i, j int) bool {
+	return h.less(h.elems[i], h.elems[j])
+}
+
+func (h *heapItems[E]) Swap(i, j int) {
+	h.elems[i], h.elems[j] = h.elems[j], h.elems[i]
+}
+
+func (h *heapItems[E]) Push(x any) {
+	h.elems = append(h.elems, x.(E))
+}
+
+func (h *heapItems[E]) Pop() any {
+	n := len(h.elems) - 1
+	e := h.elems[n]
+	var zero E
+	h.elems[n] = zero
+	h.elems = h.elems[:n]
+	return e
+}
@@ -0,0 +1,38 @@
+package conv
+
+import "testing"
+
+func TestHeap(t *testing.T) {
+	a := []int{5, 1, 4, 2}
+	h := NewHeap(a, func(a, b int) bool { return a < b })
+	h.Push(3)
+	if h.Len() != 5 || len(h.Slice()) != 5 {
+		t.Fatal(h.Len())
+	}
+	if e, ok := h.Peek(); !ok || e != 1 {
+		t.Fatal(e, ok)
+	}
+
+	var popped []int
+	for {
+		e, ok := h.Pop()
+		if !ok {
+			break
+		}
+		popped = append(popped, e)
+	}
+	if diff := diffSlice([]int{1, 2, 3, 4, 5}, popped); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{5, 1, 4, 2}, a); diff != "" {
+		t.Fatal(diff)
+	}
+	if _, ok := h.Peek(); ok {
+		t.Fatal("should be empty")
+	}
+
+	max := NewHeap([]string{"a", "c", "b"}, func(a, b string) bool { return a > b })
+	if e, _ := max.Pop(); e != "c" {
+		t.Fatal(e)
+	}
+}
@@ -0,0 +1,262 @@
+package conv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Signed is a constraint that permits any signed integer type
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is a constraint that permits any unsigned integer type
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is a constraint that permits any integer type
+type Integer interface {
+	Signed | Unsigned
+}
+
+// LeadingZeroPolicy decides how a leading zero is interpreted when the base is implied by the string
+type LeadingZeroPolicy int
+
+const (
+	// LeadingZeroDecimal treats "010" as decimal 10
+	LeadingZeroDecimal LeadingZeroPolicy = iota
+	// LeadingZeroOctal treats "010" as octal 8, as strconv.ParseInt(s, 0, 64) does
+	LeadingZeroOctal
+	// LeadingZeroReject rejects numbers with leading zeros, e.g. "010" or "0x01"
+	LeadingZeroReject
+)
+
+type ParseIntOptions struct {
+	// Base is the numeric base between 2 and 36.
+	// If Base is 0, it is implied by the prefix: "0b" for 2, "0o" for 8, "0x" for 16, and 10 otherwise.
+	// A prefix matching an explicit base 2, 8 or 16 is accepted as well.
+	Base int
+
+	// LeadingZeros decides how leading zeros are handled
+	LeadingZeros LeadingZeroPolicy
+
+	// AllowUnderscores permits "_" between digits, e.g. "1_000_000"
+	AllowUnderscores bool
+
+	// TrimSpace removes leading and trailing white spaces before parsing
+	TrimSpace bool
+}
+
+// ParseInt parses s as a signed integer of type T
+// The returned error is a *strconv.NumError wrapping strconv.ErrSyntax or strconv.ErrRange
+func ParseInt[T Signed](s string, optFns ...func(options *ParseIntOptions)) (T, error) {
+	options := &ParseIntOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	neg, digits, base, err := splitInteger(s, options)
+	if err != nil {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: err}
+	}
+
+	var zero T
+	bitSize := int(unsafe.Sizeof(zero) * 8)
+	u, err := strconv.ParseUint(digits, base, 64)
+	if err != nil {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: err.(*strconv.NumError).Err}
+	}
+
+	limit := uint64(1) << (bitSize - 1)
+	if (!neg && u >= limit) || (neg && u > limit) {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrRange}
+	}
+
+	if neg {
+		return T(-int64(u)), nil
+	}
+	return T(u), nil
+}
+
+// ParseUint parses s as an unsigned integer of type T
+// The returned error is a *strconv.NumError wrapping strconv.ErrSyntax or strconv.ErrRange
+func ParseUint[T Unsigned](s string, optFns ...func(options *ParseIntOptions)) (T, error) {
+	options := &ParseIntOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	neg, digits, base, err := splitInteger(s, options)
+	if err == nil && neg {
+		err = strconv.ErrSyntax
+	}
+	if err != nil {
+		return 0, &strconv.NumError{Func: "ParseUint", Num: s, Err: err}
+	}
+
+	var zero T
+	bitSize := int(unsafe.Sizeof(zero) * 8)
+	u, err := strconv.ParseUint(digits, base, bitSize)
+	if err != nil {
+		return 0, &strconv.NumError{Func: "ParseUint", Num: s, Err: err.(*strconv.NumError).Err}
+	}
+	return T(u), nil
+}
+
+// splitInteger validates s against options and returns the sign, the bare digits and the base
+func splitInteger(s string, options *ParseIntOptions) (neg bool, digits string, base int, err error) {
+	if options.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	if s == "" {
+		return false, "", 0, strconv.ErrSyntax
+	}
+
+	base = options.Base
+	if base != 0 && (base < 2 || base > 36) {
+		return false, "", 0, strconv.ErrSyntax
+	}
+
+	prefixed := false
+	if len(s) > 2 && s[0] == '0' {
+		prefixBase := 0
+		switch s[1] {
+		case 'b', 'B':
+			prefixBase = 2
+		case 'o', 'O':
+			prefixBase = 8
+		case 'x', 'X':
+			prefixBase = 16
+		}
+
+		if prefixBase != 0 && (base == 0 || base == prefixBase) {
+			base = prefixBase
+			s = s[2:]
+			prefixed = true
+			if options.AllowUnderscores && s[0] == '_' {
+				s = s[1:]
+			}
+		}
+	}
+
+	if options.AllowUnderscores {
+		if s, err = removeUnderscores(s); err != nil {
+			return false, "", 0, err
+		}
+	}
+
+	if s == "" {
+		return false, "", 0, strconv.ErrSyntax
+	}
+
+	if len(s) > 1 && s[0] == '0' {
+		switch {
+		case options.LeadingZeros == LeadingZeroReject:
+			return false, "", 0, strconv.ErrSyntax
+		case base == 0 && !prefixed && options.LeadingZeros == LeadingZeroOctal:
+			base = 8
+		}
+	}
+
+	if base == 0 {
+		base = 10
+	}
+	return neg, s, base, nil
+}
+
+// removeUnderscores removes "_" which must appear between two digits
+func removeUnderscores(s string) (string, error) {
+	if strings.IndexByte(s, '_') < 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		if i == 0 || i == len(s)-1 || s[i-1] == '_' || s[i+1] == '_' {
+			return "", strconv.ErrSyntax
+		}
+	}
+	return b.String(), nil
+}
+
+type FormatIntOptions struct {
+	// Base is the numeric base between 2 and 36, default is 10
+	Base int
+
+	// Width is the minimum number of digits, zeros are padded on the left
+	Width int
+
+	// Prefix adds "0b", "0o" or "0x" for base 2, 8 or 16
+	Prefix bool
+
+	// Uppercase uses upper case letters for digits greater than 9
+	Uppercase bool
+}
+
+// FormatInt returns the string representation of v
+// The returned error wraps strconv.ErrSyntax if Base is invalid, as ParseInt does
+func FormatInt[T Integer](v T, optFns ...func(options *FormatIntOptions)) (string, error) {
+	options := &FormatIntOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	base := options.Base
+	if base == 0 {
+		base = 10
+	}
+	if base < 2 || base > 36 {
+		return "", fmt.Errorf("invalid base %d: %w", options.Base, strconv.ErrSyntax)
+	}
+
+	neg := v < 0
+	var u uint64
+	if neg {
+		u = uint64(-int64(v))
+	} else {
+		u = uint64(v)
+	}
+
+	digits := strconv.FormatUint(u, base)
+	if options.Uppercase {
+		digits = strings.ToUpper(digits)
+	}
+
+	var b strings.Builder
+	b.Grow(len(digits) + options.Width + 3)
+	if neg {
+		b.WriteByte('-')
+	}
+
+	if options.Prefix {
+		switch base {
+		case 2:
+			b.WriteString("0b")
+		case 8:
+			b.WriteString("0o")
+		case 16:
+			b.WriteString("0x")
+		}
+	}
+
+	for i := len(digits); i < options.Width; i++ {
+		b.WriteByte('0')
+	}
+	b.WriteString(digits)
+	return b.String(), nil
+}
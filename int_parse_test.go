@@ -0,0 +1,151 @@
+package conv
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestParseInt(t *testing.T) {
+	goodCases := []struct {
+		Value   string
+		Options ParseIntOptions
+		Result  int64
+	}{
+		{"123", ParseIntOptions{}, 123},
+		{"-123", ParseIntOptions{}, -123},
+		{"+123", ParseIntOptions{}, 123},
+		{"010", ParseIntOptions{}, 10},
+		{"010", ParseIntOptions{LeadingZeros: LeadingZeroOctal}, 8},
+		{"0x1F", ParseIntOptions{}, 31},
+		{"-0x1f", ParseIntOptions{}, -31},
+		{"0b101", ParseIntOptions{}, 5},
+		{"0o17", ParseIntOptions{}, 15},
+		{"1F", ParseIntOptions{Base: 16}, 31},
+		{"0x1F", ParseIntOptions{Base: 16}, 31},
+		{"1_000_000", ParseIntOptions{AllowUnderscores: true}, 1000000},
+		{"0x_ff_ff", ParseIntOptions{AllowUnderscores: true}, 65535},
+		{" 42\n", ParseIntOptions{TrimSpace: true}, 42},
+		{"0", ParseIntOptions{LeadingZeros: LeadingZeroReject}, 0},
+		{"9223372036854775807", ParseIntOptions{}, math.MaxInt64},
+		{"-9223372036854775808", ParseIntOptions{}, math.MinInt64},
+	}
+
+	t.Run("Good", func(t *testing.T) {
+		for _, c := range goodCases {
+			options := c.Options
+			res, err := ParseInt[int64](c.Value, func(o *ParseIntOptions) {
+				*o = options
+			})
+			if err != nil {
+				t.Error(err, c.Value)
+				continue
+			}
+			if res != c.Result {
+				t.Errorf("%q: expect %d, got %d", c.Value, c.Result, res)
+			}
+		}
+	})
+
+	badCases := []struct {
+		Value   string
+		Options ParseIntOptions
+		Err     error
+	}{
+		{"", ParseIntOptions{}, strconv.ErrSyntax},
+		{"-", ParseIntOptions{}, strconv.ErrSyntax},
+		{"1_000", ParseIntOptions{}, strconv.ErrSyntax},
+		{"1__000", ParseIntOptions{AllowUnderscores: true}, strconv.ErrSyntax},
+		{"_1000", ParseIntOptions{AllowUnderscores: true}, strconv.ErrSyntax},
+		{"1000_", ParseIntOptions{AllowUnderscores: true}, strconv.ErrSyntax},
+		{"010", ParseIntOptions{LeadingZeros: LeadingZeroReject}, strconv.ErrSyntax},
+		{"0x1F", ParseIntOptions{Base: 10}, strconv.ErrSyntax},
+		{" 42", ParseIntOptions{}, strconv.ErrSyntax},
+		{"12", ParseIntOptions{Base: 1}, strconv.ErrSyntax},
+		{"9223372036854775808", ParseIntOptions{}, strconv.ErrRange},
+		{"-9223372036854775809", ParseIntOptions{}, strconv.ErrRange},
+	}
+
+	t.Run("Bad", func(t *testing.T) {
+		for _, c := range badCases {
+			options := c.Options
+			_, err := ParseInt[int64](c.Value, func(o *ParseIntOptions) {
+				*o = options
+			})
+			if !errors.Is(err, c.Err) {
+				t.Errorf("%q: expect %v, got %v", c.Value, c.Err, err)
+			}
+		}
+	})
+
+	t.Run("BitSize", func(t *testing.T) {
+		type Int8 int8
+		if v, err := ParseInt[Int8]("-128"); err != nil || v != math.MinInt8 {
+			t.Fatal(v, err)
+		}
+		if _, err := ParseInt[Int8]("128"); !errors.Is(err, strconv.ErrRange) {
+			t.Fatal(err)
+		}
+		if v, err := ParseInt[int16]("0x7fff"); err != nil || v != math.MaxInt16 {
+			t.Fatal(v, err)
+		}
+	})
+}
+
+func TestParseUint(t *testing.T) {
+	if v, err := ParseUint[uint64]("18446744073709551615"); err != nil || v != math.MaxUint64 {
+		t.Fatal(v, err)
+	}
+	if v, err := ParseUint[uint8]("0xff"); err != nil || v != math.MaxUint8 {
+		t.Fatal(v, err)
+	}
+	if _, err := ParseUint[uint8]("256"); !errors.Is(err, strconv.ErrRange) {
+		t.Fatal(err)
+	}
+	if _, err := ParseUint[uint]("-1"); !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatal(err)
+	}
+	if v, err := ParseUint[uint16]("777", func(o *ParseIntOptions) { o.Base = 8 }); err != nil || v != 511 {
+		t.Fatal(v, err)
+	}
+}
+
+func TestFormatInt(t *testing.T) {
+	cases := []struct {
+		Value   int64
+		Options FormatIntOptions
+		Result  string
+	}{
+		{123, FormatIntOptions{}, "123"},
+		{-123, FormatIntOptions{}, "-123"},
+		{255, FormatIntOptions{Base: 16}, "ff"},
+		{255, FormatIntOptions{Base: 16, Uppercase: true, Prefix: true}, "0xFF"},
+		{-255, FormatIntOptions{Base: 16, Prefix: true, Width: 4}, "-0x00ff"},
+		{5, FormatIntOptions{Base: 2, Prefix: true, Width: 8}, "0b00000101"},
+		{8, FormatIntOptions{Base: 8, Prefix: true}, "0o10"},
+		{7, FormatIntOptions{Width: 3}, "007"},
+		{1234, FormatIntOptions{Width: 3}, "1234"},
+		{math.MinInt64, FormatIntOptions{}, "-9223372036854775808"},
+	}
+
+	for _, c := range cases {
+		options := c.Options
+		res, err := FormatInt(c.Value, func(o *FormatIntOptions) {
+			*o = options
+		})
+		if err != nil || res != c.Result {
+			t.Errorf("expect %s, got %s, %v", c.Result, res, err)
+		}
+	}
+
+	if s, err := FormatInt(uint64(math.MaxUint64), func(o *FormatIntOptions) { o.Base = 16 }); err != nil || s != "ffffffffffffffff" {
+		t.Fatal(s, err)
+	}
+
+	for _, base := range []int{-1, 1, 37} {
+		if _, err := FormatInt(10, func(o *FormatIntOptions) { o.Base = base }); !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("base %d: %v", base, err)
+		}
+	}
+}
@@ -1,6 +1,28 @@
 package conv
 
-import "encoding/json"
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONOptions controls how JSON data is decoded
+type JSONOptions struct {
+	// UseNumber decodes numbers into interface values as json.Number instead of float64,
+	// so that integers above 2^53 keep their precision, e.g. when converted with ToInt64
+	UseNumber bool
+
+	// DisallowUnknownFields fails decoding if an object key does not match any struct field
+	DisallowUnknownFields bool
+}
 
 func MustToJSONBytes(v any) []byte {
 	data, err := json.Marshal(v)
@@ -31,3 +53,268 @@ func MustFromJSONString(s string, v any) {
 		panic(err)
 	}
 }
+
+// FromJSON decodes b into a value of type T
+func FromJSON[T any](b []byte, optFns ...func(options *JSONOptions)) (T, error) {
+	var v T
+	err := DecodeJSON(b, &v, optFns...)
+	return v, err
+}
+
+// DecodeJSON decodes b into v like json.Unmarshal with options
+func DecodeJSON(b []byte, v any, optFns ...func(options *JSONOptions)) error {
+	options := &JSONOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	dec := newJSONDecoder(bytes.NewReader(b), options)
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level value")
+	}
+	return nil
+}
+
+func newJSONDecoder(r io.Reader, options *JSONOptions) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if options.UseNumber {
+		dec.UseNumber()
+	}
+	if options.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec
+}
+
+// CanonicalJSON encodes v into the JSON Canonicalization Scheme defined in RFC 8785:
+// no whitespace, object keys sorted by UTF-16 code units, numbers formatted as ECMAScript does
+// and strings with minimal escaping. Pass json.RawMessage to canonicalize existing JSON data
+func CanonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	if err = DecodeJSON(data, &generic, func(options *JSONOptions) {
+		options.UseNumber = true
+	}); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = writeCanonicalJSON(&buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("number %s: %w", v, err)
+		}
+		buf.WriteString(formatES6Number(f))
+	case string:
+		writeCanonicalJSONString(buf, v)
+	case []any:
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalJSONString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonicalJSON(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+	return nil
+}
+
+// formatES6Number formats f in the same way as Number.prototype.toString in ECMAScript
+func formatES6Number(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	abs := math.Abs(f)
+	if abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	// ECMAScript has no leading zero in the exponent, e.g. 1e-7 instead of 1e-07
+	if n := len(s); n >= 4 && s[n-2] == '0' && (s[n-3] == '-' || s[n-3] == '+') {
+		s = s[:n-2] + s[n-1:]
+	}
+	return s
+}
+
+func writeCanonicalJSONString(buf *bytes.Buffer, s string) {
+	const hexDigits = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[r>>4])
+				buf.WriteByte(hexDigits[r&0xF])
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 compares strings by their UTF-16 code units as required by RFC 8785
+func lessUTF16(a, b string) bool {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			ua, ub := utf16Units(ra), utf16Units(rb)
+			if ua[0] != ub[0] {
+				return ua[0] < ub[0]
+			}
+			return ua[1] < ub[1]
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) < len(b)
+}
+
+func utf16Units(r rune) [2]rune {
+	if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+		return [2]rune{r1, r2}
+	}
+	return [2]rune{r, 0}
+}
+
+// PrettyJSON re-formats JSON data b with indent for each nesting level
+func PrettyJSON(b []byte, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CompactJSON removes insignificant whitespace from JSON data b
+func CompactJSON(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NDJSONReader reads values of type T from newline delimited JSON
+type NDJSONReader[T any] struct {
+	r       *bufio.Reader
+	options *JSONOptions
+	line    int
+}
+
+// NewNDJSONReader creates a reader of newline delimited JSON, blank lines are skipped
+func NewNDJSONReader[T any](r io.Reader, optFns ...func(options *JSONOptions)) *NDJSONReader[T] {
+	options := &JSONOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	return &NDJSONReader[T]{
+		r:       bufio.NewReader(r),
+		options: options,
+	}
+}
+
+// Read returns the next value, or io.EOF if there is no more value
+func (r *NDJSONReader[T]) Read() (T, error) {
+	var v T
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return v, err
+		}
+		if err != nil && err != io.EOF {
+			return v, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if err = DecodeJSON(line, &v, func(options *JSONOptions) {
+			*options = *r.options
+		}); err != nil {
+			return v, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		return v, nil
+	}
+}
+
+// NDJSONWriter writes values of type T as newline delimited JSON
+type NDJSONWriter[T any] struct {
+	w io.Writer
+}
+
+func NewNDJSONWriter[T any](w io.Writer) *NDJSONWriter[T] {
+	return &NDJSONWriter[T]{w: w}
+}
+
+// Write writes v in one line
+func (w *NDJSONWriter[T]) Write(v T) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
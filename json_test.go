@@ -0,0 +1,213 @@
+package conv
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFromJSON(t *testing.T) {
+	type Item struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	t.Run("Good", func(t *testing.T) {
+		item, err := FromJSON[Item]([]byte(`{"id":9007199254740993,"name":"a"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.ID != 9007199254740993 || item.Name != "a" {
+			t.Fatal(item)
+		}
+
+		m, err := FromJSON[map[string]any]([]byte(`{"id":9007199254740993}`), func(options *JSONOptions) {
+			options.UseNumber = true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m["id"].(json.Number); !ok {
+			t.Fatalf("%T", m["id"])
+		}
+		if id, err := ToInt64(m["id"]); err != nil || id != 9007199254740993 {
+			t.Fatal(id, err)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		if _, err := FromJSON[Item]([]byte(`{"id":1,"age":2}`), func(options *JSONOptions) {
+			options.DisallowUnknownFields = true
+		}); err == nil {
+			t.Fatal("should fail")
+		}
+		for _, s := range []string{``, `{"id":1}{}`, `{"id":1}]`, `{"id":"1"}`} {
+			if _, err := FromJSON[Item]([]byte(s)); err == nil {
+				t.Errorf("%s: should fail", s)
+			}
+		}
+	})
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	t.Run("Example", func(t *testing.T) {
+		input := `{
+  "numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+  "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+  "literals": [null, true, false]
+}`
+		expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`
+		b, err := CanonicalJSON(json.RawMessage(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Fatalf("expect %s, got %s", expected, b)
+		}
+	})
+
+	t.Run("Sorting", func(t *testing.T) {
+		input := `{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`
+		expected := "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"
+		b, err := CanonicalJSON(json.RawMessage(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Fatalf("expect %s, got %s", expected, b)
+		}
+	})
+
+	t.Run("Numbers", func(t *testing.T) {
+		cases := []struct {
+			Bits   uint64
+			Result string
+		}{
+			{0x0000000000000000, "0"},
+			{0x8000000000000000, "0"},
+			{0x0000000000000001, "5e-324"},
+			{0x8000000000000001, "-5e-324"},
+			{0x7fefffffffffffff, "1.7976931348623157e+308"},
+			{0xffefffffffffffff, "-1.7976931348623157e+308"},
+			{0x4340000000000000, "9007199254740992"},
+			{0xc340000000000000, "-9007199254740992"},
+			{0x4430000000000000, "295147905179352830000"},
+			{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+			{0x44b52d02c7e14af6, "1e+23"},
+			{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+			{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+			{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+			{0x444b1ae4d6e2ef50, "1e+21"},
+			{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+			{0x3eb0c6f7a0b5ed8d, "0.000001"},
+			{0x41b3de4355555553, "333333333.3333332"},
+			{0x41b3de4355555554, "333333333.33333325"},
+			{0x41b3de4355555555, "333333333.3333333"},
+			{0x41b3de4355555556, "333333333.3333334"},
+			{0x41b3de4355555557, "333333333.33333343"},
+			{0xbecbf647612f3696, "-0.0000033333333333333333"},
+			{0x43143ff3c1cb0959, "1424953923781206.2"},
+		}
+		for _, c := range cases {
+			f := math.Float64frombits(c.Bits)
+			b, err := CanonicalJSON(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != c.Result {
+				t.Errorf("%x: expect %s, got %s", c.Bits, c.Result, b)
+			}
+		}
+
+		if _, err := CanonicalJSON(math.NaN()); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+
+	t.Run("Struct", func(t *testing.T) {
+		v := struct {
+			B string `json:"b"`
+			A []int  `json:"a"`
+		}{B: "<&>", A: []int{1, 2}}
+		b, err := CanonicalJSON(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `{"a":[1,2],"b":"<&>"}` {
+			t.Fatal(string(b))
+		}
+	})
+}
+
+func TestPrettyJSON(t *testing.T) {
+	b, err := PrettyJSON([]byte(`{"a":[1,2]}`), "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "{\n  \"a\": [\n    1,\n    2\n  ]\n}" {
+		t.Fatal(string(b))
+	}
+
+	b, err = CompactJSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"a":[1,2]}` {
+		t.Fatal(string(b))
+	}
+
+	if _, err = PrettyJSON([]byte(`{"a":`), "  "); err == nil {
+		t.Fatal("should fail")
+	}
+	if _, err = CompactJSON([]byte(`{"a":`)); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	type Event struct {
+		ID   int64  `json:"id"`
+		Kind string `json:"kind"`
+	}
+
+	var buf bytes.Buffer
+	w := NewNDJSONWriter[Event](&buf)
+	for i := 1; i <= 3; i++ {
+		if err := w.Write(Event{ID: int64(i), Kind: "click"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Fatal(n)
+	}
+
+	buf.WriteString("\n{\"id\":4}")
+	r := NewNDJSONReader[Event](&buf)
+	var events []Event
+	for {
+		e, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	if len(events) != 4 || events[0].ID != 1 || events[3].ID != 4 || events[3].Kind != "" {
+		t.Fatal(events)
+	}
+
+	r = NewNDJSONReader[Event](strings.NewReader("{\"id\":1}\n{\"id\":1,\"x\":2}\n"), func(options *JSONOptions) {
+		options.DisallowUnknownFields = true
+	})
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatal(err)
+	}
+}
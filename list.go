@@ -2,11 +2,14 @@ package conv
 
 import (
 	"container/list"
+	"container/ring"
+	"fmt"
 	"reflect"
 )
 
 // ToList creates list.List
-// i can be nil, *list.List, or array/slice
+// i can be nil, *list.List, array/slice, map or channel.
+// Map entries are pushed as Pair[any, any] in unspecified order, and channels are received until closed
 func ToList(i any) *list.List {
 	if i == nil {
 		return list.New()
@@ -23,11 +26,29 @@ func ToList(i any) *list.List {
 
 	l := list.New()
 	v := reflect.ValueOf(Indirect(i))
-	if v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && !v.IsNil() {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
 		for j := 0; j < v.Len(); j++ {
 			l.PushBack(v.Index(j).Interface())
 		}
-	} else {
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			l.PushBack(Pair[any, any]{First: iter.Key().Interface(), Second: iter.Value().Interface()})
+		}
+	case reflect.Chan:
+		if v.Type().ChanDir()&reflect.RecvDir == 0 || v.IsNil() {
+			l.PushBack(i)
+			break
+		}
+		for {
+			e, ok := v.Recv()
+			if !ok {
+				break
+			}
+			l.PushBack(e.Interface())
+		}
+	default:
 		l.PushBack(i)
 	}
 	return l
@@ -41,3 +62,165 @@ func SliceToList[E any](a []E) *list.List {
 	}
 	return l
 }
+
+// ListToSlice converts list.List to slice
+// It panics if any element is not of type E, use ListToSliceE to get an error instead
+func ListToSlice[E any](l *list.List) []E {
+	a, err := ListToSliceE[E](l)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// ListToSliceE converts list.List to slice, or returns an error if any element is not of type E
+func ListToSliceE[E any](l *list.List) ([]E, error) {
+	if l == nil {
+		return nil, nil
+	}
+	a := make([]E, 0, l.Len())
+	i := 0
+	for elem := l.Front(); elem != nil; elem = elem.Next() {
+		e, ok := elem.Value.(E)
+		if !ok {
+			var zero E
+			return nil, fmt.Errorf("[%d]: cannot convert %#v of type %T to %T", i, elem.Value, elem.Value, zero)
+		}
+		a = append(a, e)
+		i++
+	}
+	return a, nil
+}
+
+// List is a typed wrapper of list.List
+// The zero value is an empty list ready to use
+type List[E any] struct {
+	l list.List
+}
+
+// NewList creates a list of elems
+func NewList[E any](elems ...E) *List[E] {
+	l := new(List[E])
+	for _, e := range elems {
+		l.PushBack(e)
+	}
+	return l
+}
+
+func (l *List[E]) PushBack(e E) {
+	l.l.PushBack(e)
+}
+
+func (l *List[E]) PushFront(e E) {
+	l.l.PushFront(e)
+}
+
+// Front returns the first element, or false if l is empty
+func (l *List[E]) Front() (E, bool) {
+	return listValue[E](l.l.Front())
+}
+
+// Back returns the last element, or false if l is empty
+func (l *List[E]) Back() (E, bool) {
+	return listValue[E](l.l.Back())
+}
+
+// PopFront removes and returns the first element, or false if l is empty
+func (l *List[E]) PopFront() (E, bool) {
+	elem := l.l.Front()
+	if elem == nil {
+		var zero E
+		return zero, false
+	}
+	return l.l.Remove(elem).(E), true
+}
+
+// PopBack removes and returns the last element, or false if l is empty
+func (l *List[E]) PopBack() (E, bool) {
+	elem := l.l.Back()
+	if elem == nil {
+		var zero E
+		return zero, false
+	}
+	return l.l.Remove(elem).(E), true
+}
+
+func (l *List[E]) Len() int {
+	return l.l.Len()
+}
+
+// Range calls fn for each element from front to back until fn returns false
+func (l *List[E]) Range(fn func(e E) bool) {
+	for elem := l.l.Front(); elem != nil; elem = elem.Next() {
+		if !fn(elem.Value.(E)) {
+			return
+		}
+	}
+}
+
+// Slice returns elements from front to back
+func (l *List[E]) Slice() []E {
+	return ListToSlice[E](&l.l)
+}
+
+// List returns the underlying list.List
+// Pushing values of other types into it makes l panic
+func (l *List[E]) List() *list.List {
+	return &l.l
+}
+
+func listValue[E any](elem *list.Element) (E, bool) {
+	if elem == nil {
+		var zero E
+		return zero, false
+	}
+	return elem.Value.(E), true
+}
+
+// SliceToRing converts slice to ring.Ring, or returns nil if a is empty
+func SliceToRing[E any](a []E) *ring.Ring {
+	if len(a) == 0 {
+		return nil
+	}
+	r := ring.New(len(a))
+	for _, e := range a {
+		r.Value = e
+		r = r.Next()
+	}
+	return r
+}
+
+// RingToSlice converts ring.Ring to slice starting from r
+// It panics if any element is not of type E, use RingToSliceE to get an error instead
+func RingToSlice[E any](r *ring.Ring) []E {
+	a, err := RingToSliceE[E](r)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// RingToSliceE converts ring.Ring to slice starting from r, or returns an error if any element is not of type E
+func RingToSliceE[E any](r *ring.Ring) ([]E, error) {
+	if r == nil {
+		return nil, nil
+	}
+	a := make([]E, 0, r.Len())
+	var err error
+	r.Do(func(v any) {
+		if err != nil {
+			return
+		}
+		e, ok := v.(E)
+		if !ok {
+			var zero E
+			err = fmt.Errorf("[%d]: cannot convert %#v of type %T to %T", len(a), v, v, zero)
+			return
+		}
+		a = append(a, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
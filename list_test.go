@@ -0,0 +1,144 @@
+package conv
+
+import (
+	"container/list"
+	"sort"
+	"testing"
+)
+
+func TestToList(t *testing.T) {
+	t.Run("Good", func(t *testing.T) {
+		cases := []struct {
+			Value  any
+			Result []any
+		}{
+			{nil, []any{}},
+			{[]int{1, 2}, []any{1, 2}},
+			{[2]string{"a", "b"}, []any{"a", "b"}},
+			{&[]int{3}, []any{3}},
+			{[]int(nil), []any{}},
+			{1, []any{1}},
+			{map[string]int{"a": 1}, []any{Pair[any, any]{First: "a", Second: 1}}},
+		}
+		for _, c := range cases {
+			got := ListToSlice[any](ToList(c.Value))
+			if diff := diffSlice(c.Result, got); diff != "" {
+				t.Errorf("%#v: %s", c.Value, diff)
+			}
+		}
+	})
+
+	t.Run("Chan", func(t *testing.T) {
+		ch := make(chan int, 3)
+		ch <- 1
+		ch <- 2
+		close(ch)
+		if diff := diffSlice([]any{1, 2}, ListToSlice[any](ToList(ch))); diff != "" {
+			t.Fatal(diff)
+		}
+
+		var sendOnly chan<- int = make(chan int)
+		if l := ToList(sendOnly); l.Len() != 1 {
+			t.Fatal(l.Len())
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		pairs := ListToSlice[Pair[any, any]](ToList(map[int]string{1: "a", 2: "b"}))
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].First.(int) < pairs[j].First.(int)
+		})
+		if diff := diffSlice([]Pair[any, any]{{1, "a"}, {2, "b"}}, pairs); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+}
+
+func TestListToSliceE(t *testing.T) {
+	l := SliceToList([]int{1, 2, 3})
+	a, err := ListToSliceE[int](l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]int{1, 2, 3}, a); diff != "" {
+		t.Fatal(diff)
+	}
+
+	l.PushBack("4")
+	if _, err = ListToSliceE[int](l); err == nil {
+		t.Fatal("should fail")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("should panic")
+			}
+		}()
+		ListToSlice[int](l)
+	}()
+
+	if a, err = ListToSliceE[int](nil); a != nil || err != nil {
+		t.Fatal(a, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	var l List[int]
+	if _, ok := l.PopFront(); ok {
+		t.Fatal("should be empty")
+	}
+	l.PushBack(2)
+	l.PushFront(1)
+	l.PushBack(3)
+	if front, _ := l.Front(); front != 1 {
+		t.Fatal(front)
+	}
+	if back, _ := l.Back(); back != 3 {
+		t.Fatal(back)
+	}
+	if diff := diffSlice([]int{1, 2, 3}, l.Slice()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	var visited []int
+	l.Range(func(e int) bool {
+		visited = append(visited, e)
+		return e < 2
+	})
+	if diff := diffSlice([]int{1, 2}, visited); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if e, ok := l.PopBack(); !ok || e != 3 {
+		t.Fatal(e, ok)
+	}
+	if e, ok := l.PopFront(); !ok || e != 1 {
+		t.Fatal(e, ok)
+	}
+	if l.Len() != 1 || l.List().Len() != 1 {
+		t.Fatal(l.Len())
+	}
+
+	if diff := diffSlice([]string{"a", "b"}, NewList("a", "b").Slice()); diff != "" {
+		t.Fatal(diff)
+	}
+	var _ *list.List = NewList[int]().List()
+}
+
+func TestRing(t *testing.T) {
+	r := SliceToRing([]int{1, 2, 3})
+	if diff := diffSlice([]int{1, 2, 3}, RingToSlice[int](r)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{2, 3, 1}, RingToSlice[int](r.Next())); diff != "" {
+		t.Fatal(diff)
+	}
+	if SliceToRing([]int{}) != nil || RingToSlice[int](nil) != nil {
+		t.Fatal("should be nil")
+	}
+
+	r.Value = "x"
+	if _, err := RingToSliceE[int](r); err == nil {
+		t.Fatal("should fail")
+	}
+}
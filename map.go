@@ -0,0 +1,296 @@
+package conv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrDuplicateKey means a key exists in more than one map when merging with MergeError,
+// or different keys are converted to the same key by ToMapOf
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// MergeStrategy decides which value is kept if a key exists in more than one map
+type MergeStrategy int
+
+const (
+	// MergeOverwrite keeps the value of the last map
+	MergeOverwrite MergeStrategy = iota
+	// MergeKeepFirst keeps the value of the first map
+	MergeKeepFirst
+	// MergeError fails with ErrDuplicateKey
+	MergeError
+)
+
+// ToStringMap converts i to map[string]any
+// i can be a map whose keys are convertible with ToString, a struct, or a JSON object in string or []byte.
+// Struct fields are named by json tags if any.
+// The result is always a new map, so it's safe to modify it even if i is map[string]any
+func ToStringMap(i any) (map[string]any, error) {
+	i = Indirect(i)
+	switch v := i.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return jsonToStringMap([]byte(v))
+	case []byte:
+		return jsonToStringMap(v)
+	}
+
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := ToString(iter.Key().Interface())
+			if err != nil {
+				return nil, fmt.Errorf("convert key %v: %w", iter.Key(), err)
+			}
+			m[k] = iter.Value().Interface()
+		}
+		return m, nil
+	case reflect.Struct:
+		m := make(map[string]any, v.NumField())
+		structToStringMap(m, v)
+		return m, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %#v of type %T to map[string]any", i, i)
+	}
+}
+
+func jsonToStringMap(b []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("cannot convert to map[string]any: %w", err)
+	}
+	return m, nil
+}
+
+func structToStringMap(m map[string]any, v reflect.Value) {
+	for _, f := range structFields(v.Type(), "json", nil) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// nil embedded pointer
+			continue
+		}
+		if hasTagOption(f.opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		m[f.name] = fv.Interface()
+	}
+}
+
+// ToMapOf converts i to map[K]V, keys and values are converted with ToXxx converters
+// e.g. map[string]any{"1": "2"} can be converted to map[int]int64, and strings like "2s" are parsed as time.Duration.
+// Numeric strings are parsed in base 10 and numbers must fit into numeric types without loss, e.g. "1.9" and 1.9 fail for int.
+// An error wrapping ErrDuplicateKey is returned if different keys are converted to the same key, e.g. "1" and "01" to int
+func ToMapOf[K comparable, V any](i any) (map[K]V, error) {
+	m, err := toAnyMap(i)
+	if err != nil || m == nil {
+		return nil, err
+	}
+
+	res := make(map[K]V, len(m))
+	for k, v := range m {
+		var key K
+		if err = convertValue(reflect.ValueOf(&key).Elem(), k); err != nil {
+			return nil, fmt.Errorf("convert key %v: %w", k, err)
+		}
+		if _, ok := res[key]; ok {
+			return nil, fmt.Errorf("convert key %v to %v: %w", k, key, ErrDuplicateKey)
+		}
+		var value V
+		if err = convertValue(reflect.ValueOf(&value).Elem(), v); err != nil {
+			return nil, fmt.Errorf("convert value of %v: %w", k, err)
+		}
+		res[key] = value
+	}
+	return res, nil
+}
+
+func toAnyMap(i any) (map[any]any, error) {
+	if v := reflect.ValueOf(Indirect(i)); v.Kind() == reflect.Map {
+		m := make(map[any]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().Interface()] = iter.Value().Interface()
+		}
+		return m, nil
+	}
+	sm, err := ToStringMap(i)
+	if err != nil || sm == nil {
+		return nil, err
+	}
+	m := make(map[any]any, len(sm))
+	for k, v := range sm {
+		m[k] = v
+	}
+	return m, nil
+}
+
+// convertValue converts src with ToXxx converters and sets it to dst
+// Strings are parsed by time.ParseDuration for time.Duration instead of being treated as nanoseconds
+func convertValue(dst reflect.Value, src any) error {
+	if s, ok := src.(string); ok && dst.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+	if IsIntValue(dst) || isCastUintValue(dst) || IsFloatValue(dst) {
+		return convertNumber(dst, src)
+	}
+	err := scanValue(dst, src)
+	if err == nil {
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct, reflect.Ptr:
+		// composite values, e.g. []any to []int
+		if assignErr := unsafeAssign(dst, reflect.ValueOf(src), &UnsafeAssignOptions{
+			FieldNameMatcher: fieldNameEqual{},
+		}); assignErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// convertNumber sets src to dst of a numeric kind
+// Text is parsed in base 10 and numbers must be exactly representable by dst, e.g. "010" is 10 and 1.9 is not an int
+func convertNumber(dst reflect.Value, src any) error {
+	if b, ok := src.([]byte); ok {
+		src = string(b)
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Kind() == reflect.String {
+		var err error
+		switch {
+		case IsIntValue(dst):
+			src, err = strconv.ParseInt(sv.String(), 10, 64)
+		case isCastUintValue(dst):
+			src, err = strconv.ParseUint(sv.String(), 10, 64)
+		default:
+			src, err = strconv.ParseFloat(sv.String(), 64)
+		}
+		if err != nil {
+			return err
+		}
+		sv = reflect.ValueOf(src)
+	}
+	if !IsIntValue(sv) && !isCastUintValue(sv) && !IsFloatValue(sv) {
+		// e.g. nil and bool
+		return scanValue(dst, src)
+	}
+	if err := checkCast(dst, sv); err != nil {
+		return err
+	}
+	dst.Set(sv.Convert(dst.Type()))
+	return nil
+}
+
+// Keys returns keys of m in unspecified order
+func Keys[K comparable, V any](m map[K]V) []K {
+	l := make([]K, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	return l
+}
+
+// Values returns values of m in unspecified order
+func Values[K comparable, V any](m map[K]V) []V {
+	l := make([]V, 0, len(m))
+	for _, v := range m {
+		l = append(l, v)
+	}
+	return l
+}
+
+// SortedKeys returns keys of m in ascending order
+func SortedKeys[K Ordered, V any](m map[K]V) []K {
+	l := Keys(m)
+	sort.Slice(l, func(i, j int) bool {
+		return l[i] < l[j]
+	})
+	return l
+}
+
+// Invert swaps keys and values of m
+// If values are duplicated, which key is kept is unspecified
+func Invert[K comparable, V comparable](m map[K]V) map[V]K {
+	res := make(map[V]K, len(m))
+	for k, v := range m {
+		res[v] = k
+	}
+	return res
+}
+
+// MergeMaps merges maps into a new map, strategy decides which value is kept for duplicate keys
+func MergeMaps[K comparable, V any](strategy MergeStrategy, maps ...map[K]V) (map[K]V, error) {
+	n := 0
+	for _, m := range maps {
+		n += len(m)
+	}
+	res := make(map[K]V, n)
+	for _, m := range maps {
+		for k, v := range m {
+			if _, ok := res[k]; ok {
+				switch strategy {
+				case MergeKeepFirst:
+					continue
+				case MergeError:
+					return nil, fmt.Errorf("%v: %w", k, ErrDuplicateKey)
+				}
+			}
+			res[k] = v
+		}
+	}
+	return res, nil
+}
+
+// FilterMap returns entries of m for which pred returns true
+func FilterMap[K comparable, V any](m map[K]V, pred func(k K, v V) bool) map[K]V {
+	res := make(map[K]V)
+	for k, v := range m {
+		if pred(k, v) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// MapValues transforms values of m
+func MapValues[K comparable, V any, R any](m map[K]V, fn func(v V) R) map[K]R {
+	res := make(map[K]R, len(m))
+	for k, v := range m {
+		res[k] = fn(v)
+	}
+	return res
+}
+
+// MapToSlice transforms entries of m into a slice in unspecified order
+func MapToSlice[K comparable, V any, E any](m map[K]V, fn func(k K, v V) E) []E {
+	res := make([]E, 0, len(m))
+	for k, v := range m {
+		res = append(res, fn(k, v))
+	}
+	return res
+}
+
+// SliceToMap transforms elements of a into entries, later entries overwrite earlier ones with the same key
+func SliceToMap[E any, K comparable, V any](a []E, fn func(e E) (K, V)) map[K]V {
+	res := make(map[K]V, len(a))
+	for _, e := range a {
+		k, v := fn(e)
+		res[k] = v
+	}
+	return res
+}
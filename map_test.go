@@ -0,0 +1,205 @@
+package conv
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToStringMap(t *testing.T) {
+	type Base struct {
+		ID int `json:"id"`
+	}
+
+	type User struct {
+		Base
+		Name     string `json:"name"`
+		Nickname string `json:"nickname,omitempty"`
+		Password string `json:"-"`
+		Age      int
+		secret   string
+	}
+
+	t.Run("Good", func(t *testing.T) {
+		cases := []struct {
+			Value  any
+			Result map[string]any
+		}{
+			{nil, nil},
+			{map[string]any{"a": 1}, map[string]any{"a": 1}},
+			{map[int]string{1: "a"}, map[string]any{"1": "a"}},
+			{&map[bool]int{true: 1}, map[string]any{"true": 1}},
+			{`{"a":[1]}`, map[string]any{"a": []any{1.0}}},
+			{[]byte(`{"a":null}`), map[string]any{"a": nil}},
+			{User{Base: Base{ID: 1}, Name: "Tom", Password: "x", Age: 3}, map[string]any{"id": 1, "name": "Tom", "Age": 3}},
+		}
+		for _, c := range cases {
+			m, err := ToStringMap(c.Value)
+			if err != nil {
+				t.Fatal(c.Value, err)
+			}
+			if !reflect.DeepEqual(c.Result, m) {
+				t.Errorf("expect %v, got %v", c.Result, m)
+			}
+		}
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		src := map[string]any{"a": 1}
+		m, err := ToStringMap(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m["b"] = 2
+		if len(src) != 1 {
+			t.Fatal(src)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		for _, v := range []any{1, "[]", []int{1}, map[[2]int]int{{1, 2}: 1}} {
+			if _, err := ToStringMap(v); err == nil {
+				t.Errorf("%#v: should fail", v)
+			}
+		}
+	})
+}
+
+func TestToMapOf(t *testing.T) {
+	m, err := ToMapOf[int, int64](map[string]any{"1": "2", "3": 4.0, "5": []byte("6")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, map[int]int64{1: 2, 3: 4, 5: 6}) {
+		t.Fatal(m)
+	}
+
+	l, err := ToMapOf[string, []int](`{"a":[1,2]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]int{1, 2}, l["a"]); diff != "" {
+		t.Fatal(diff)
+	}
+
+	d, err := ToMapOf[string, time.Duration](map[string]any{"a": "1m30s", "b": 5, "c": int64(time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d, map[string]time.Duration{"a": 90 * time.Second, "b": 5, "c": time.Second}) {
+		t.Fatal(d)
+	}
+	if _, err = ToMapOf[string, time.Duration](map[string]any{"a": "1 minute"}); err == nil {
+		t.Fatal("should fail")
+	}
+
+	n, err := ToMapOf[string, int](map[string]any{"a": "010", "b": 2.0, "c": []byte("-3"), "d": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, map[string]int{"a": 10, "b": 2, "c": -3, "d": 1}) {
+		t.Fatal(n)
+	}
+	f, err := ToMapOf[string, float32](map[string]any{"a": "1.5", "b": 2})
+	if err != nil || !reflect.DeepEqual(f, map[string]float32{"a": 1.5, "b": 2}) {
+		t.Fatal(f, err)
+	}
+	for _, v := range []any{"1.9", 1.9, "0x10", math.Inf(1)} {
+		if _, err = ToMapOf[string, int](map[string]any{"a": v}); err == nil {
+			t.Errorf("%#v: should fail", v)
+		}
+	}
+	for _, v := range []any{"-1", -1, "256"} {
+		if _, err = ToMapOf[string, uint8](map[string]any{"a": v}); err == nil {
+			t.Errorf("%#v: should fail", v)
+		}
+	}
+	if _, err = ToMapOf[string, float32](map[string]any{"a": 1e300}); !errors.Is(err, strconv.ErrRange) {
+		t.Fatal(err)
+	}
+
+	if _, err = ToMapOf[int8, int](map[string]int{"300": 1}); err == nil {
+		t.Fatal("should fail")
+	}
+	if _, err = ToMapOf[string, bool](map[string]string{"a": "maybe"}); err == nil {
+		t.Fatal("should fail")
+	}
+	if _, err = ToMapOf[int, string](map[string]any{"1": "a", "01": "b"}); !errors.Is(err, ErrDuplicateKey) {
+		t.Fatal(err)
+	}
+	if m, err = ToMapOf[int, int64](nil); err != nil || m != nil {
+		t.Fatal(m, err)
+	}
+}
+
+func TestMapHelpers(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	if diff := diffSlice([]string{"a", "b", "c"}, keys); diff != "" {
+		t.Fatal(diff)
+	}
+	values := Values(m)
+	sort.Ints(values)
+	if diff := diffSlice([]int{1, 2, 3}, values); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]string{"a", "b", "c"}, SortedKeys(m)); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if inv := Invert(m); !reflect.DeepEqual(inv, map[int]string{1: "a", 2: "b", 3: "c"}) {
+		t.Fatal(inv)
+	}
+
+	odd := FilterMap(m, func(k string, v int) bool { return v%2 == 1 })
+	if !reflect.DeepEqual(odd, map[string]int{"a": 1, "c": 3}) {
+		t.Fatal(odd)
+	}
+
+	s := MapValues(m, strconv.Itoa)
+	if !reflect.DeepEqual(s, map[string]string{"a": "1", "b": "2", "c": "3"}) {
+		t.Fatal(s)
+	}
+
+	entries := MapToSlice(m, func(k string, v int) string { return k + "=" + strconv.Itoa(v) })
+	sort.Strings(entries)
+	if diff := diffSlice([]string{"a=1", "b=2", "c=3"}, entries); diff != "" {
+		t.Fatal(diff)
+	}
+
+	back := SliceToMap(entries, func(e string) (string, int) {
+		k, v, _ := strings.Cut(e, "=")
+		n, _ := strconv.Atoi(v)
+		return k, n
+	})
+	if !reflect.DeepEqual(back, m) {
+		t.Fatal(back)
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]int{"y": 20, "z": 30}
+
+	m, err := MergeMaps(MergeOverwrite, a, b)
+	if err != nil || !reflect.DeepEqual(m, map[string]int{"x": 1, "y": 20, "z": 30}) {
+		t.Fatal(m, err)
+	}
+	m, err = MergeMaps(MergeKeepFirst, a, b, nil)
+	if err != nil || !reflect.DeepEqual(m, map[string]int{"x": 1, "y": 2, "z": 30}) {
+		t.Fatal(m, err)
+	}
+	if _, err = MergeMaps(MergeError, a, b); !errors.Is(err, ErrDuplicateKey) {
+		t.Fatal(err)
+	}
+	if m, err = MergeMaps[string, int](MergeError); err != nil || len(m) != 0 {
+		t.Fatal(m, err)
+	}
+}
@@ -0,0 +1,592 @@
+package conv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// MsgPackTimeExtType is the MessagePack extension type of timestamps
+const MsgPackTimeExtType int8 = -1
+
+// maxDecodeDepth limits nesting of decoded arrays and maps, so that malicious input cannot overflow the stack
+const maxDecodeDepth = 10000
+
+// MsgPackExt is a MessagePack extension value of an application defined type
+type MsgPackExt struct {
+	Type int8
+	Data []byte
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	msgPackExtType = reflect.TypeOf(MsgPackExt{})
+	textMarshaler  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ToMsgPack encodes v in MessagePack format
+// Struct fields are encoded as maps keyed by msgpack tag names or field names, embedded structs are flattened.
+// time.Time is encoded as timestamp extension, encoding.TextMarshaler as string.
+func ToMsgPack(v any) ([]byte, error) {
+	return appendMsgPack(nil, reflect.ValueOf(v))
+}
+
+// FromMsgPack decodes MessagePack data b into dst which must be a non-nil pointer
+// Data is decoded into generic values first, then assigned to dst in the same way as UnsafeAssign.
+// msgpack is the default tag name.
+func FromMsgPack(b []byte, dst any, optFns ...func(options *UnsafeAssignOptions)) error {
+	r := bytes.NewReader(b)
+	if err := NewMsgPackDecoder(r).Decode(dst, optFns...); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("msgpack: %d bytes remain after decoding", r.Len())
+	}
+	return nil
+}
+
+// MsgPackEncoder writes MessagePack values to an output stream
+type MsgPackEncoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+func NewMsgPackEncoder(w io.Writer) *MsgPackEncoder {
+	return &MsgPackEncoder{w: w}
+}
+
+// Encode writes the MessagePack encoding of v to the stream
+func (e *MsgPackEncoder) Encode(v any) error {
+	b, err := appendMsgPack(e.buf[:0], reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+	e.buf = b
+	_, err = e.w.Write(b)
+	return err
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// MsgPackDecoder reads MessagePack values from an input stream
+type MsgPackDecoder struct {
+	r     byteReader
+	depth int
+}
+
+func NewMsgPackDecoder(r io.Reader) *MsgPackDecoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &MsgPackDecoder{r: br}
+}
+
+// Decode reads the next value from the stream and stores it in dst which must be a non-nil pointer
+// io.EOF is returned at the end of the stream.
+// Maps are decoded as map[string]any, arrays as []any, integers as int64 or uint64 if exceeding math.MaxInt64,
+// floats as float64, timestamps as time.Time and other extensions as MsgPackExt.
+func (d *MsgPackDecoder) Decode(dst any, optFns ...func(options *UnsafeAssignOptions)) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("cannot decode into %T", dst)
+	}
+
+	options := &UnsafeAssignOptions{TagName: "msgpack", Overwrite: true}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	if options.FieldNameMatcher == nil {
+		options.FieldNameMatcher = fieldNameEqual{}
+	}
+
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	v, err := d.decodeValue(c)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("msgpack: %w", err)
+	}
+
+	if err = unsafeAssign(dv.Elem(), reflect.ValueOf(v), options); err != nil {
+		return fmt.Errorf("cannot assign %T to %T: %w", v, dst, err)
+	}
+	return nil
+}
+
+func (d *MsgPackDecoder) decodeValue(c byte) (any, error) {
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readLength(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.readN(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.readLength(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(n)
+	case 0xca:
+		u, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.readUint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.readUint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		if u > math.MaxInt64 {
+			return u, nil
+		}
+		return int64(u), nil
+	case 0xd0:
+		u, err := d.readUint(1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := d.readUint(2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := d.readUint(4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := d.readUint(8)
+		return int64(u), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readLength(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(n)
+	case 0xdc, 0xdd:
+		n, err := d.readLength(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n)
+	case 0xde, 0xdf:
+		n, err := d.readLength(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n)
+	default:
+		return nil, fmt.Errorf("invalid code 0x%x", c)
+	}
+}
+
+func (d *MsgPackDecoder) decodeString(n int) (string, error) {
+	b, err := d.readN(n)
+	return string(b), err
+}
+
+func (d *MsgPackDecoder) decodeArray(n int) ([]any, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	a := make([]any, 0, minInt(n, 1024))
+	for i := 0; i < n; i++ {
+		v, err := d.decodeNext()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (d *MsgPackDecoder) decodeMap(n int) (map[string]any, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	m := make(map[string]any, minInt(n, 1024))
+	for i := 0; i < n; i++ {
+		k, err := d.decodeNext()
+		if err != nil {
+			return nil, err
+		}
+		key, err := ToString(k)
+		if err != nil {
+			return nil, fmt.Errorf("map key: %w", err)
+		}
+		m[key], err = d.decodeNext()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (d *MsgPackDecoder) enter() error {
+	if d.depth >= maxDecodeDepth {
+		return fmt.Errorf("exceeded max depth %d", maxDecodeDepth)
+	}
+	d.depth++
+	return nil
+}
+
+func (d *MsgPackDecoder) leave() {
+	d.depth--
+}
+
+func (d *MsgPackDecoder) decodeExt(n int) (any, error) {
+	typ, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.readN(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if int8(typ) != MsgPackTimeExtType {
+		return MsgPackExt{Type: int8(typ), Data: data}, nil
+	}
+
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(u&(1<<34-1)), int64(u>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		sec := binary.BigEndian.Uint64(data[4:])
+		return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+	default:
+		return nil, fmt.Errorf("invalid timestamp length %d", n)
+	}
+}
+
+func (d *MsgPackDecoder) decodeNext() (any, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeValue(c)
+}
+
+func (d *MsgPackDecoder) readUint(size int) (uint64, error) {
+	var u uint64
+	for i := 0; i < size; i++ {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (d *MsgPackDecoder) readLength(size int) (int, error) {
+	u, err := d.readUint(size)
+	if err != nil {
+		return 0, err
+	}
+	if u > MaxInt {
+		return 0, fmt.Errorf("length %d is too large", u)
+	}
+	return int(u), nil
+}
+
+// readN reads n bytes, buffers grow with received data so that a corrupted length cannot exhaust memory
+func (d *MsgPackDecoder) readN(n int) ([]byte, error) {
+	if n <= 64*kilobyte {
+		b := make([]byte, n)
+		_, err := io.ReadFull(d.r, b)
+		return b, err
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func appendMsgPack(b []byte, v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(b, 0xc0), nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return append(b, 0xc0), nil
+	}
+
+	switch t := v.Type(); {
+	case t == timeType:
+		return appendMsgPackTime(b, v.Interface().(time.Time)), nil
+	case t == msgPackExtType:
+		ext := v.Interface().(MsgPackExt)
+		return appendMsgPackExt(b, ext.Type, ext.Data), nil
+	case t.Implements(textMarshaler):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("marshal text: %w", err)
+		}
+		return appendMsgPackString(b, string(text)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMsgPackInt(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendMsgPackUint(b, v.Uint()), nil
+	case reflect.Float32:
+		b = append(b, 0xca)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(float32(v.Float()))), nil
+	case reflect.Float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v.Float())), nil
+	case reflect.String:
+		return appendMsgPackString(b, v.String()), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return append(b, 0xc0), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return appendMsgPackBinary(b, v.Bytes()), nil
+		}
+		return appendMsgPackArray(b, v)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			return appendMsgPackBinary(b, data), nil
+		}
+		return appendMsgPackArray(b, v)
+	case reflect.Map:
+		if v.IsNil() {
+			return append(b, 0xc0), nil
+		}
+		return appendMsgPackMap(b, v)
+	case reflect.Struct:
+		return appendMsgPackStruct(b, v)
+	default:
+		return nil, fmt.Errorf("msgpack: unsupported type %v", v.Type())
+	}
+}
+
+func appendMsgPackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0:
+		return appendMsgPackUint(b, uint64(i))
+	case i >= -32:
+		return append(b, byte(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+func appendMsgPackUint(b []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(b, byte(u))
+	case u <= math.MaxUint8:
+		return append(b, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), u)
+	}
+}
+
+// appendMsgPackHeader appends the header of a string, binary, array or map
+// fix is the code of the fixed-size format or 0, codes are the formats of 8, 16 and 32 bits length or 0
+func appendMsgPackHeader(b []byte, n int, fix byte, fixMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case fix != 0 && n <= fixMax:
+		return append(b, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(b, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+	}
+}
+
+func appendMsgPackString(b []byte, s string) []byte {
+	b = appendMsgPackHeader(b, len(s), 0xa0, 31, 0xd9, 0xda, 0xdb)
+	return append(b, s...)
+}
+
+func appendMsgPackBinary(b []byte, data []byte) []byte {
+	b = appendMsgPackHeader(b, len(data), 0, 0, 0xc4, 0xc5, 0xc6)
+	return append(b, data...)
+}
+
+func appendMsgPackArray(b []byte, v reflect.Value) ([]byte, error) {
+	b = appendMsgPackHeader(b, v.Len(), 0x90, 15, 0, 0xdc, 0xdd)
+	var err error
+	for i := 0; i < v.Len(); i++ {
+		b, err = appendMsgPack(b, v.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+	return b, nil
+}
+
+func appendMsgPackMap(b []byte, v reflect.Value) ([]byte, error) {
+	keys := v.MapKeys()
+	if v.Type().Key().Kind() == reflect.String {
+		// sort keys to make the encoding deterministic
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+	}
+
+	b = appendMsgPackHeader(b, len(keys), 0x80, 15, 0, 0xde, 0xdf)
+	var err error
+	for _, k := range keys {
+		b, err = appendMsgPack(b, k)
+		if err != nil {
+			return nil, fmt.Errorf("key %v: %w", k, err)
+		}
+		b, err = appendMsgPack(b, v.MapIndex(k))
+		if err != nil {
+			return nil, fmt.Errorf("value of %v: %w", k, err)
+		}
+	}
+	return b, nil
+}
+
+type msgPackField struct {
+	name  string
+	value reflect.Value
+}
+
+func appendMsgPackStruct(b []byte, v reflect.Value) ([]byte, error) {
+	fields := collectMsgPackFields(v)
+	b = appendMsgPackHeader(b, len(fields), 0x80, 15, 0, 0xde, 0xdf)
+	var err error
+	for _, f := range fields {
+		b = appendMsgPackString(b, f.name)
+		b, err = appendMsgPack(b, f.value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return b, nil
+}
+
+func collectMsgPackFields(v reflect.Value) []msgPackField {
+	var fields []msgPackField
+	for _, f := range structFields(v.Type(), "msgpack", nil) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// nil embedded pointer
+			continue
+		}
+		if hasTagOption(f.opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		fields = append(fields, msgPackField{name: f.name, value: fv})
+	}
+	return fields
+}
+
+func appendMsgPackExt(b []byte, typ int8, data []byte) []byte {
+	switch n := len(data); n {
+	case 1:
+		b = append(b, 0xd4)
+	case 2:
+		b = append(b, 0xd5)
+	case 4:
+		b = append(b, 0xd6)
+	case 8:
+		b = append(b, 0xd7)
+	case 16:
+		b = append(b, 0xd8)
+	default:
+		b = appendMsgPackHeader(b, n, 0, 0, 0xc7, 0xc8, 0xc9)
+	}
+	b = append(b, byte(typ))
+	return append(b, data...)
+}
+
+func appendMsgPackTime(b []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	switch {
+	case sec>>34 == 0 && nsec == 0 && sec <= math.MaxUint32:
+		return appendMsgPackExt(b, MsgPackTimeExtType, binary.BigEndian.AppendUint32(nil, uint32(sec)))
+	case sec>>34 == 0:
+		return appendMsgPackExt(b, MsgPackTimeExtType, binary.BigEndian.AppendUint64(nil, uint64(nsec)<<34|uint64(sec)))
+	default:
+		data := binary.BigEndian.AppendUint32(nil, uint32(nsec))
+		return appendMsgPackExt(b, MsgPackTimeExtType, binary.BigEndian.AppendUint64(data, uint64(sec)))
+	}
+}
+
+func hasTagOption(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
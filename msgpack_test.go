@@ -0,0 +1,214 @@
+package conv
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestToMsgPack(t *testing.T) {
+	cases := []struct {
+		Value  any
+		Result string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{false, "c2"},
+		{0, "00"},
+		{127, "7f"},
+		{128, "cc80"},
+		{-1, "ff"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{65536, "ce00010000"},
+		{int64(math.MinInt64), "d38000000000000000"},
+		{uint64(math.MaxUint64), "cfffffffffffffffff"},
+		{float32(1.5), "ca3fc00000"},
+		{1.5, "cb3ff8000000000000"},
+		{"abc", "a3616263"},
+		{[]byte{1, 2}, "c4020102"},
+		{[]int{1, 2, 3}, "93010203"},
+		{map[string]any{"compact": true, "schema": 0}, "82a7636f6d70616374c3a6736368656d6100"},
+		{time.Unix(1, 0), "d6ff00000001"},
+		{time.Unix(1, 1), "d7ff0000000400000001"},
+		{time.Unix(-1, 0), "c70cff00000000ffffffffffffffff"},
+		{MsgPackExt{Type: 5, Data: []byte{1, 2, 3}}, "c70305010203"},
+	}
+
+	for _, c := range cases {
+		b, err := ToMsgPack(c.Value)
+		if err != nil {
+			t.Fatal(c.Value, err)
+		}
+		if s := hex.EncodeToString(b); s != c.Result {
+			t.Errorf("%#v: expect %s, got %s", c.Value, c.Result, s)
+		}
+	}
+
+	if _, err := ToMsgPack(make(chan int)); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestFromMsgPack(t *testing.T) {
+	type Address struct {
+		City string `msgpack:"city"`
+	}
+
+	type Base struct {
+		ID int64
+	}
+
+	type Person struct {
+		Base
+		Name      string    `msgpack:"name"`
+		Age       uint8     `msgpack:"age,omitempty"`
+		Tags      []string  `msgpack:"tags"`
+		Address   *Address  `msgpack:"address"`
+		CreatedAt time.Time `msgpack:"created_at"`
+		Secret    string    `msgpack:"-"`
+		Score     float64
+	}
+
+	t.Run("Struct", func(t *testing.T) {
+		p := Person{
+			Base:      Base{ID: 1},
+			Name:      "Tom",
+			Tags:      []string{"a", "b"},
+			Address:   &Address{City: "Paris"},
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+			Secret:    "secret",
+			Score:     9.5,
+		}
+		b, err := ToMsgPack(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var m map[string]any
+		if err = FromMsgPack(b, &m); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m["age"]; ok {
+			t.Fatal("age should be omitted")
+		}
+		if _, ok := m["Secret"]; ok {
+			t.Fatal("secret should be skipped")
+		}
+		if m["ID"] != int64(1) || m["Score"] != 9.5 {
+			t.Fatal(m)
+		}
+
+		var p2 Person
+		if err = FromMsgPack(b, &p2); err != nil {
+			t.Fatal(err)
+		}
+		if p2.ID != 1 || p2.Name != "Tom" || p2.Address == nil || p2.Address.City != "Paris" || p2.Score != 9.5 {
+			t.Fatalf("%#v", p2)
+		}
+		if !p2.CreatedAt.Equal(p.CreatedAt) {
+			t.Fatal(p2.CreatedAt)
+		}
+		if diff := diffSlice(p.Tags, p2.Tags); diff != "" {
+			t.Fatal(diff)
+		}
+		if p2.Secret != "" {
+			t.Fatal(p2.Secret)
+		}
+	})
+
+	t.Run("Any", func(t *testing.T) {
+		b, _ := hex.DecodeString("93cfffffffffffffffffc0ca3fc00000")
+		var v any
+		if err := FromMsgPack(b, &v); err != nil {
+			t.Fatal(err)
+		}
+		a := v.([]any)
+		if a[0] != uint64(math.MaxUint64) || a[1] != nil || a[2] != 1.5 {
+			t.Fatal(a)
+		}
+	})
+
+	t.Run("Numbers", func(t *testing.T) {
+		b, _ := ToMsgPack(map[int]int{1: -2})
+		var m map[string]int8
+		if err := FromMsgPack(b, &m); err != nil {
+			t.Fatal(err)
+		}
+		if m["1"] != -2 {
+			t.Fatal(m)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		var v any
+		if err := FromMsgPack([]byte{0xc1}, &v); err == nil {
+			t.Fatal("should fail")
+		}
+		if err := FromMsgPack([]byte{0xa3, 'a'}, &v); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatal(err)
+		}
+		if err := FromMsgPack([]byte{0xc0, 0xc0}, &v); err == nil {
+			t.Fatal("should fail")
+		}
+		if err := FromMsgPack([]byte{0xc0}, v); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
+
+func TestMsgPackDepth(t *testing.T) {
+	nested := func(depth int) []byte {
+		return append(bytes.Repeat([]byte{0x91}, depth), 0x01)
+	}
+
+	var v any
+	if err := FromMsgPack(nested(maxDecodeDepth), &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := FromMsgPack(nested(maxDecodeDepth+1), &v); err == nil {
+		t.Fatal("should fail")
+	}
+	if err := FromMsgPack(nested(20_000_000), &v); err == nil {
+		t.Fatal("should fail")
+	}
+
+	dec := NewMsgPackDecoder(bytes.NewReader(nested(maxDecodeDepth + 1)))
+	if err := dec.Decode(&v); err == nil || dec.depth != 0 {
+		t.Fatal(err, dec.depth)
+	}
+}
+
+func TestMsgPackStream(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewMsgPackEncoder(&buf)
+	for i := 0; i < 3; i++ {
+		if err := enc.Encode(map[string]int{"n": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dec := NewMsgPackDecoder(&buf)
+	for i := 0; ; i++ {
+		var m struct {
+			N int `msgpack:"n"`
+		}
+		err := dec.Decode(&m)
+		if err == io.EOF {
+			if i != 3 {
+				t.Fatal(i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.N != i {
+			t.Fatal(m.N, i)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package conv
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+// Option is an optional value, which is either Some or None
+// The zero value is None
+type Option[T any] struct {
+	v  T
+	ok bool
+}
+
+// Some returns an Option of v
+func Some[T any](v T) Option[T] {
+	return Option[T]{v: v, ok: true}
+}
+
+// None returns an Option without value
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// OptionFromPtr returns None if p is nil, otherwise Some of *p
+func OptionFromPtr[T any](p *T) Option[T] {
+	if p == nil {
+		return None[T]()
+	}
+	return Some(*p)
+}
+
+// OptionToPtr returns nil if o is None, otherwise a pointer to a copy of its value
+func OptionToPtr[T any](o Option[T]) *T {
+	if !o.ok {
+		return nil
+	}
+	v := o.v
+	return &v
+}
+
+// MapOption transforms the value of o, None stays None
+func MapOption[T any, R any](o Option[T], fn func(v T) R) Option[R] {
+	if !o.ok {
+		return None[R]()
+	}
+	return Some(fn(o.v))
+}
+
+func (o Option[T]) IsSome() bool {
+	return o.ok
+}
+
+func (o Option[T]) IsNone() bool {
+	return !o.ok
+}
+
+// Get returns the value and whether o is Some
+func (o Option[T]) Get() (T, bool) {
+	return o.v, o.ok
+}
+
+// OrElse returns the value if o is Some, otherwise v
+func (o Option[T]) OrElse(v T) T {
+	if o.ok {
+		return o.v
+	}
+	return v
+}
+
+// Scan implements sql.Scanner, NULL is scanned as None
+func (o *Option[T]) Scan(src any) error {
+	if src == nil {
+		*o = None[T]()
+		return nil
+	}
+	var v T
+	if err := scanValue(reflect.ValueOf(&v).Elem(), src); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// Value implements driver.Valuer, None is NULL
+func (o Option[T]) Value() (driver.Value, error) {
+	if !o.ok {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(o.v)
+}
+
+// MarshalJSON encodes None as null
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.v)
+}
+
+// UnmarshalJSON decodes null as None
+func (o *Option[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*o = None[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+func (o Option[T]) optionValue() (any, bool) {
+	return o.v, o.ok
+}
+
+func (o *Option[T]) assignOption(assign func(dst reflect.Value) error) error {
+	var v T
+	if err := assign(reflect.ValueOf(&v).Elem()); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// optionValuer is implemented by Option to be used as a source of UnsafeAssign
+type optionValuer interface {
+	optionValue() (any, bool)
+}
+
+// optionAssigner is implemented by *Option to be used as a destination of UnsafeAssign
+type optionAssigner interface {
+	assignOption(assign func(dst reflect.Value) error) error
+}
+
+// isOptionValue reports whether v is an Option or a pointer to Option
+func isOptionValue(v reflect.Value) bool {
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.PtrTo(t).Implements(optionAssignerType)
+}
+
+var optionAssignerType = reflect.TypeOf((*optionAssigner)(nil)).Elem()
+
+// Result is either a value or an error
+type Result[T any] struct {
+	v   T
+	err error
+}
+
+// Ok returns a Result of v
+func Ok[T any](v T) Result[T] {
+	return Result[T]{v: v}
+}
+
+// Err returns a Result of err
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf returns a Result of (v, err), e.g. ResultOf(strconv.Atoi(s))
+func ResultOf[T any](v T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+	return Ok(v)
+}
+
+// MapResult transforms the value of r, errors are kept as is
+func MapResult[T any, R any](r Result[T], fn func(v T) R) Result[R] {
+	if r.err != nil {
+		return Err[R](r.err)
+	}
+	return Ok(fn(r.v))
+}
+
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// Err returns the error, or nil if r is Ok
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns the value and error
+func (r Result[T]) Unwrap() (T, error) {
+	if r.err != nil {
+		var zero T
+		return zero, r.err
+	}
+	return r.v, nil
+}
+
+// OrElse returns the value if r is Ok, otherwise v
+func (r Result[T]) OrElse(v T) T {
+	if r.err != nil {
+		return v
+	}
+	return r.v
+}
+
+// Option returns Some of the value if r is Ok, otherwise None
+func (r Result[T]) Option() Option[T] {
+	if r.err != nil {
+		return None[T]()
+	}
+	return Some(r.v)
+}
@@ -0,0 +1,183 @@
+package conv
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestOption(t *testing.T) {
+	some := Some(3)
+	if v, ok := some.Get(); !ok || v != 3 || !some.IsSome() {
+		t.Fatal(v, ok)
+	}
+	none := None[int]()
+	if none.IsSome() || !none.IsNone() || none.OrElse(5) != 5 || some.OrElse(5) != 3 {
+		t.Fatal(none)
+	}
+	if s := MapOption(some, strconv.Itoa); s.OrElse("") != "3" {
+		t.Fatal(s)
+	}
+	if s := MapOption(none, strconv.Itoa); s.IsSome() {
+		t.Fatal(s)
+	}
+
+	if p := OptionToPtr(some); p == nil || *p != 3 {
+		t.Fatal(p)
+	}
+	if p := OptionToPtr(none); p != nil {
+		t.Fatal(p)
+	}
+	if o := OptionFromPtr(Pointer(4)); o.OrElse(0) != 4 {
+		t.Fatal(o)
+	}
+	if o := OptionFromPtr[int](nil); o.IsSome() {
+		t.Fatal(o)
+	}
+}
+
+func TestOptionJSON(t *testing.T) {
+	type Item struct {
+		Count Option[int]    `json:"count"`
+		Name  Option[string] `json:"name"`
+	}
+
+	b, err := json.Marshal(Item{Count: Some(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"count":0,"name":null}` {
+		t.Fatal(string(b))
+	}
+
+	var item Item
+	if err = json.Unmarshal([]byte(`{"count":null,"name":"a"}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Count.IsSome() || item.Name.OrElse("") != "a" {
+		t.Fatal(item)
+	}
+	if err = json.Unmarshal([]byte(`{"count":"x"}`), &item); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestOptionSQL(t *testing.T) {
+	var o Option[int64]
+	if err := o.Scan([]byte("12")); err != nil || o.OrElse(0) != 12 {
+		t.Fatal(o, err)
+	}
+	if err := o.Scan(nil); err != nil || o.IsSome() {
+		t.Fatal(o, err)
+	}
+
+	var _ driver.Valuer = o
+	if v, err := o.Value(); v != nil || err != nil {
+		t.Fatal(v, err)
+	}
+	if v, err := Some(int8(1)).Value(); v != int64(1) || err != nil {
+		t.Fatal(v, err)
+	}
+}
+
+func TestOptionUnsafeAssign(t *testing.T) {
+	type User struct {
+		Name string
+		Age  Option[int]
+		Nick Option[string]
+	}
+
+	var u User
+	err := UnsafeAssign(&u, map[string]any{"Name": "Tom", "Age": "3", "Nick": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "Tom" || u.Age.OrElse(0) != 3 || u.Nick.IsSome() {
+		t.Fatal(u)
+	}
+
+	type Plain struct {
+		Name *string
+		Age  int
+	}
+	var p Plain
+	err = UnsafeAssign(&p, struct {
+		Name Option[string]
+		Age  Option[int]
+	}{Age: Some(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != nil || p.Age != 5 {
+		t.Fatal(p)
+	}
+}
+
+func TestOptionUnsafeAssignNone(t *testing.T) {
+	type Patch struct {
+		Name Option[string]
+		Age  Option[int]
+	}
+
+	type Plain struct {
+		Name string
+		Age  int
+	}
+	plain := Plain{Name: "x", Age: 3}
+	err := unsafeAssign(reflect.ValueOf(&plain).Elem(), reflect.ValueOf(Patch{Age: Some(5)}), &UnsafeAssignOptions{
+		FieldNameMatcher: fieldNameEqual{},
+		Overwrite:        true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Name != "x" || plain.Age != 5 {
+		t.Fatal(plain)
+	}
+
+	type Optional struct {
+		Name Option[string]
+		Age  *Option[int]
+	}
+	age := Some(1)
+	optional := Optional{Name: Some("x"), Age: &age}
+	err = unsafeAssign(reflect.ValueOf(&optional).Elem(), reflect.ValueOf(Patch{}), &UnsafeAssignOptions{
+		FieldNameMatcher: fieldNameEqual{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if optional.Name.IsSome() || optional.Age != nil {
+		t.Fatal(optional)
+	}
+}
+
+func TestResult(t *testing.T) {
+	r := ResultOf(strconv.Atoi("12"))
+	if v, err := r.Unwrap(); err != nil || v != 12 || !r.IsOk() {
+		t.Fatal(v, err)
+	}
+	if s := MapResult(r, strconv.Itoa); s.OrElse("") != "12" {
+		t.Fatal(s)
+	}
+	if o := r.Option(); o.OrElse(0) != 12 {
+		t.Fatal(o)
+	}
+
+	r = ResultOf(strconv.Atoi("x"))
+	if r.IsOk() || !errors.Is(r.Err(), strconv.ErrSyntax) || r.OrElse(-1) != -1 || r.Option().IsSome() {
+		t.Fatal(r)
+	}
+	if _, err := MapResult(r, strconv.Itoa).Unwrap(); !errors.Is(err, strconv.ErrSyntax) {
+		t.Fatal(err)
+	}
+	if v, err := Ok("a").Unwrap(); v != "a" || err != nil {
+		t.Fatal(v, err)
+	}
+	if _, err := Err[int](errors.New("bad")).Unwrap(); err == nil {
+		t.Fatal("should fail")
+	}
+}
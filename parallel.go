@@ -0,0 +1,168 @@
+package conv
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ParallelOptions controls how ParallelTransform runs transformers
+type ParallelOptions struct {
+	// Concurrency is the max number of running transformers, runtime.GOMAXPROCS(0) by default
+	Concurrency int
+
+	// CollectErrors runs all transformers and returns IndexErrors instead of stopping at the first error
+	CollectErrors bool
+
+	// RecoverPanics converts panics in transformers into errors
+	RecoverPanics bool
+}
+
+// Concurrency sets the max number of running transformers
+func Concurrency(n int) func(options *ParallelOptions) {
+	return func(options *ParallelOptions) {
+		options.Concurrency = n
+	}
+}
+
+// CollectErrors runs all transformers and returns errors of all failed elements
+func CollectErrors() func(options *ParallelOptions) {
+	return func(options *ParallelOptions) {
+		options.CollectErrors = true
+	}
+}
+
+// RecoverPanics converts panics in transformers into errors
+func RecoverPanics() func(options *ParallelOptions) {
+	return func(options *ParallelOptions) {
+		options.RecoverPanics = true
+	}
+}
+
+// IndexError is an error of the element at Index
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("[%d]: %v", e.Index, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// IndexErrors are errors of elements sorted by index
+type IndexErrors []*IndexError
+
+func (e IndexErrors) Error() string {
+	l := make([]string, len(e))
+	for i, err := range e {
+		l[i] = err.Error()
+	}
+	return strings.Join(l, "; ")
+}
+
+func (e IndexErrors) Unwrap() []error {
+	l := make([]error, len(e))
+	for i, err := range e {
+		l[i] = err
+	}
+	return l
+}
+
+// ParallelTransform transforms elements of a concurrently and returns results in the order of a
+// By default, it stops at the first error, cancels the context passed to running transformers and returns the error as *IndexError.
+// With CollectErrors, all elements are transformed, and results are returned along with IndexErrors of failed elements.
+// If ctx is done before all elements are transformed, ctx.Err() is returned
+func ParallelTransform[E1 any, E2 any](ctx context.Context, a []E1, fn func(ctx context.Context, e E1) (E2, error), optFns ...func(options *ParallelOptions)) ([]E2, error) {
+	options := &ParallelOptions{
+		Concurrency: runtime.GOMAXPROCS(0),
+	}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	workers := options.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(a) {
+		workers = len(a)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		res      = make([]E2, len(a))
+		mu       sync.Mutex
+		errs     IndexErrors
+		done     int
+		indices  = make(chan int)
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				if ctx.Err() != nil {
+					continue
+				}
+				v, err := callTransform(ctx, fn, a[i], options.RecoverPanics)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, &IndexError{Index: i, Err: err})
+					if !options.CollectErrors && firstErr == nil {
+						firstErr = errs[len(errs)-1]
+						cancel()
+					}
+				} else {
+					res[i] = v
+				}
+				done++
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for i := range a {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if done < len(a) && parent.Err() != nil {
+		return nil, parent.Err()
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			return errs[i].Index < errs[j].Index
+		})
+		return res, errs
+	}
+	return res, nil
+}
+
+func callTransform[E1 any, E2 any](ctx context.Context, fn func(ctx context.Context, e E1) (E2, error), e E1, recoverPanics bool) (v E2, err error) {
+	if recoverPanics {
+		defer Recover(&err)
+	}
+	return fn(ctx, e)
+}
@@ -0,0 +1,113 @@
+package conv
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelTransform(t *testing.T) {
+	ids := []int{1, 2, 3, 4, 5, 6, 7, 8}
+
+	t.Run("Order", func(t *testing.T) {
+		var running, maxRunning int32
+		res, err := ParallelTransform(context.Background(), ids, func(ctx context.Context, id int) (string, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Duration(8-id) * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return strconv.Itoa(id), nil
+		}, Concurrency(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := diffSlice([]string{"1", "2", "3", "4", "5", "6", "7", "8"}, res); diff != "" {
+			t.Fatal(diff)
+		}
+		if maxRunning > 3 {
+			t.Fatal(maxRunning)
+		}
+
+		res, err = ParallelTransform(context.Background(), []int{}, func(ctx context.Context, id int) (string, error) {
+			return "", nil
+		})
+		if err != nil || len(res) != 0 {
+			t.Fatal(res, err)
+		}
+	})
+
+	t.Run("FailFast", func(t *testing.T) {
+		errBad := errors.New("bad")
+		var calls int32
+		_, err := ParallelTransform(context.Background(), ids, func(ctx context.Context, id int) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			if id == 2 {
+				return 0, errBad
+			}
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(time.Second):
+				return id, nil
+			}
+		}, Concurrency(2))
+		var indexErr *IndexError
+		if !errors.As(err, &indexErr) || indexErr.Index != 1 || !errors.Is(err, errBad) {
+			t.Fatal(err)
+		}
+		if calls > 3 {
+			t.Fatal(calls)
+		}
+	})
+
+	t.Run("CollectErrors", func(t *testing.T) {
+		res, err := ParallelTransform(context.Background(), ids, func(ctx context.Context, id int) (int, error) {
+			if id%3 == 0 {
+				return 0, errors.New("multiple of 3")
+			}
+			return id * 10, nil
+		}, CollectErrors(), Concurrency(4))
+		var errs IndexErrors
+		if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Index != 2 || errs[1].Index != 5 {
+			t.Fatal(err)
+		}
+		if diff := diffSlice([]int{10, 20, 0, 40, 50, 0, 70, 80}, res); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("RecoverPanics", func(t *testing.T) {
+		_, err := ParallelTransform(context.Background(), ids, func(ctx context.Context, id int) (int, error) {
+			if id == 5 {
+				panic("boom")
+			}
+			return id, nil
+		}, RecoverPanics(), CollectErrors())
+		var errs IndexErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Index != 4 || errs[0].Err.Error() != "panic: boom" {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err := ParallelTransform(ctx, ids, func(ctx context.Context, id int) (int, error) {
+			if id == 1 {
+				cancel()
+			}
+			<-ctx.Done()
+			return id, nil
+		}, Concurrency(1), CollectErrors())
+		if !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,599 @@
+package conv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RelaxedJSONSyntaxError describes a syntax error in relaxed JSON data
+// Line and Column are 1-based, Column counts characters rather than bytes
+type RelaxedJSONSyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *RelaxedJSONSyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// FromRelaxedJSON decodes relaxed JSON data b into v, which is a subset of JSON5 for hand-edited config files:
+// // and /* */ comments, trailing commas, single-quoted strings, unquoted identifier keys,
+// hexadecimal numbers, leading or trailing decimal point, leading plus sign, Infinity and NaN.
+// Data is decoded with encoding/json, so json tags and json.Unmarshaler work as usual
+func FromRelaxedJSON(b []byte, v any) error {
+	p := &relaxedJSONParser{src: b}
+	if err := p.parse(); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(p.out, v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, col := p.position(p.sourceOffset(int(syntaxErr.Offset) - 1))
+			return &RelaxedJSONSyntaxError{Line: line, Column: col, Msg: syntaxErr.Error()}
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			line, col := p.position(p.sourceOffset(int(typeErr.Offset) - 1))
+			return fmt.Errorf("line %d, column %d: %w", line, col, err)
+		}
+		return err
+	}
+
+	if len(p.nonFinites) > 0 {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return fmt.Errorf("cannot decode into %T", v)
+		}
+		for _, nf := range p.nonFinites {
+			if err := setRelaxedJSONPath(rv.Elem(), nf.path, nf.value); err != nil {
+				line, col := p.position(nf.offset)
+				return fmt.Errorf("line %d, column %d: %w", line, col, err)
+			}
+		}
+	}
+	return nil
+}
+
+// relaxedJSONNonFinite records Infinity or NaN which cannot be represented in strict JSON
+type relaxedJSONNonFinite struct {
+	path   []any
+	value  float64
+	offset int
+}
+
+// relaxedJSONParser transpiles relaxed JSON into strict JSON
+// offsets[i] is the offset in src of out[i], so that errors reported by encoding/json can be mapped back
+type relaxedJSONParser struct {
+	src        []byte
+	pos        int
+	out        []byte
+	offsets    []int
+	path       []any
+	nonFinites []relaxedJSONNonFinite
+}
+
+func (p *relaxedJSONParser) parse() error {
+	// skip UTF-8 BOM
+	if len(p.src) >= 3 && p.src[0] == 0xEF && p.src[1] == 0xBB && p.src[2] == 0xBF {
+		p.pos = 3
+	}
+	if err := p.skipSpace(); err != nil {
+		return err
+	}
+	if err := p.parseValue(); err != nil {
+		return err
+	}
+	if err := p.skipSpace(); err != nil {
+		return err
+	}
+	if p.pos < len(p.src) {
+		return p.errorf("invalid character %s after top-level value", p.quoteChar())
+	}
+	return nil
+}
+
+func (p *relaxedJSONParser) emit(s string, offset int) {
+	p.out = append(p.out, s...)
+	for range s {
+		p.offsets = append(p.offsets, offset)
+	}
+}
+
+func (p *relaxedJSONParser) emitByte(c byte, offset int) {
+	p.out = append(p.out, c)
+	p.offsets = append(p.offsets, offset)
+}
+
+func (p *relaxedJSONParser) sourceOffset(outOffset int) int {
+	if len(p.offsets) == 0 {
+		return 0
+	}
+	if outOffset < 0 {
+		outOffset = 0
+	}
+	if outOffset >= len(p.offsets) {
+		return len(p.src)
+	}
+	return p.offsets[outOffset]
+}
+
+func (p *relaxedJSONParser) position(offset int) (line, col int) {
+	if offset > len(p.src) {
+		offset = len(p.src)
+	}
+	line = 1
+	start := 0
+	for i := 0; i < offset; i++ {
+		if p.src[i] == '\n' {
+			line++
+			start = i + 1
+		}
+	}
+	return line, utf8.RuneCount(p.src[start:offset]) + 1
+}
+
+func (p *relaxedJSONParser) errorf(format string, args ...any) error {
+	line, col := p.position(p.pos)
+	return &RelaxedJSONSyntaxError{Line: line, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *relaxedJSONParser) quoteChar() string {
+	if p.pos >= len(p.src) {
+		return "EOF"
+	}
+	r, _ := utf8.DecodeRune(p.src[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *relaxedJSONParser) skipSpace() error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f':
+			p.pos++
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*':
+			end := strings.Index(string(p.src[p.pos+2:]), "*/")
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *relaxedJSONParser) parseValue() error {
+	if p.pos >= len(p.src) {
+		return p.errorf("unexpected EOF")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case isIdentifierStart(c):
+		start := p.pos
+		id := p.readIdentifier()
+		switch id {
+		case "true", "false", "null":
+			p.emit(id, start)
+			return nil
+		case "Infinity", "NaN":
+			p.pos = start
+			return p.parseNumber()
+		}
+		p.pos = start
+		return p.errorf("invalid literal %q", id)
+	default:
+		return p.errorf("invalid character %s looking for beginning of value", p.quoteChar())
+	}
+}
+
+func (p *relaxedJSONParser) parseObject() error {
+	if len(p.path) >= maxDecodeDepth {
+		return p.errorf("exceeded max depth %d", maxDecodeDepth)
+	}
+	p.emitByte('{', p.pos)
+	p.pos++
+	for i := 0; ; i++ {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.emitByte('}', p.pos)
+			p.pos++
+			return nil
+		}
+		if i > 0 {
+			p.emitByte(',', p.pos)
+		}
+
+		key, err := p.parseKey()
+		if err != nil {
+			return err
+		}
+		if err = p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return p.errorf("invalid character %s after object key", p.quoteChar())
+		}
+		p.emitByte(':', p.pos)
+		p.pos++
+		if err = p.skipSpace(); err != nil {
+			return err
+		}
+
+		p.path = append(p.path, key)
+		if err = p.parseValue(); err != nil {
+			return err
+		}
+		p.path = p.path[:len(p.path)-1]
+
+		if err = p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) {
+			return p.errorf("unexpected EOF")
+		}
+		switch p.src[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return p.errorf("invalid character %s after object value", p.quoteChar())
+		}
+	}
+}
+
+func (p *relaxedJSONParser) parseKey() (string, error) {
+	if p.pos >= len(p.src) {
+		return "", p.errorf("unexpected EOF")
+	}
+	c := p.src[p.pos]
+	if c == '"' || c == '\'' {
+		start := len(p.out)
+		if err := p.parseString(); err != nil {
+			return "", err
+		}
+		var key string
+		if err := json.Unmarshal(p.out[start:], &key); err != nil {
+			return "", p.errorf("invalid key: %v", err)
+		}
+		return key, nil
+	}
+	if !isIdentifierStart(c) {
+		return "", p.errorf("invalid character %s looking for beginning of object key", p.quoteChar())
+	}
+	start := p.pos
+	key := p.readIdentifier()
+	p.emitByte('"', start)
+	p.emit(key, start)
+	p.emitByte('"', start)
+	return key, nil
+}
+
+func (p *relaxedJSONParser) parseArray() error {
+	if len(p.path) >= maxDecodeDepth {
+		return p.errorf("exceeded max depth %d", maxDecodeDepth)
+	}
+	p.emitByte('[', p.pos)
+	p.pos++
+	for i := 0; ; i++ {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ']' {
+			p.emitByte(']', p.pos)
+			p.pos++
+			return nil
+		}
+		if i > 0 {
+			p.emitByte(',', p.pos)
+		}
+
+		p.path = append(p.path, i)
+		if err := p.parseValue(); err != nil {
+			return err
+		}
+		p.path = p.path[:len(p.path)-1]
+
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) {
+			return p.errorf("unexpected EOF")
+		}
+		switch p.src[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return p.errorf("invalid character %s after array element", p.quoteChar())
+		}
+	}
+}
+
+// parseString copies a double or single quoted string as a double quoted JSON string,
+// escapes which are valid in JSON are kept as they are
+func (p *relaxedJSONParser) parseString() error {
+	quote := p.src[p.pos]
+	p.emitByte('"', p.pos)
+	p.pos++
+	for {
+		if p.pos >= len(p.src) {
+			return p.errorf("unterminated string")
+		}
+		start := p.pos
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.emitByte('"', start)
+			p.pos++
+			return nil
+		case c == '"':
+			p.emit(`\"`, start)
+			p.pos++
+		case c < 0x20:
+			return p.errorf("invalid character %s in string literal", p.quoteChar())
+		case c == '\\':
+			if p.pos+1 >= len(p.src) {
+				return p.errorf("unterminated string")
+			}
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				p.emitByte('\\', start)
+				p.emitByte(e, start)
+				p.pos++
+			case '\'':
+				p.emitByte('\'', start)
+				p.pos++
+			case 'v':
+				p.emit(`\u000b`, start)
+				p.pos++
+			case '0':
+				p.emit(`\u0000`, start)
+				p.pos++
+			case 'u', 'x':
+				n := 4
+				if e == 'x' {
+					n = 2
+				}
+				if p.pos+n >= len(p.src) || !isHexDigits(p.src[p.pos+1:p.pos+1+n]) {
+					return p.errorf("invalid escape sequence")
+				}
+				p.emit(`\u`+strings.Repeat("0", 4-n), start)
+				p.emit(string(p.src[p.pos+1:p.pos+1+n]), start)
+				p.pos += n + 1
+			case '\n':
+				// line continuation
+				p.pos++
+			case '\r':
+				p.pos++
+				if p.pos < len(p.src) && p.src[p.pos] == '\n' {
+					p.pos++
+				}
+			default:
+				return p.errorf("invalid escape sequence")
+			}
+		default:
+			p.emitByte(c, start)
+			p.pos++
+		}
+	}
+}
+
+func (p *relaxedJSONParser) parseNumber() error {
+	start := p.pos
+	neg := false
+	if c := p.src[p.pos]; c == '+' || c == '-' {
+		neg = c == '-'
+		p.pos++
+	}
+
+	if p.pos < len(p.src) && isIdentifierStart(p.src[p.pos]) {
+		idPos := p.pos
+		switch id := p.readIdentifier(); id {
+		case "Infinity", "NaN":
+			f := math.Inf(1)
+			if id == "NaN" {
+				f = math.NaN()
+			} else if neg {
+				f = math.Inf(-1)
+			}
+			p.nonFinites = append(p.nonFinites, relaxedJSONNonFinite{
+				path:   append([]any(nil), p.path...),
+				value:  f,
+				offset: start,
+			})
+			// placeholder which will be replaced after decoding
+			p.emitByte('0', start)
+			return nil
+		default:
+			p.pos = idPos
+			return p.errorf("invalid number literal %q", id)
+		}
+	}
+
+	if neg {
+		p.emitByte('-', start)
+	}
+
+	if p.pos+1 < len(p.src) && p.src[p.pos] == '0' && (p.src[p.pos+1] == 'x' || p.src[p.pos+1] == 'X') {
+		p.pos += 2
+		digitsStart := p.pos
+		for p.pos < len(p.src) && isHexDigits(p.src[p.pos:p.pos+1]) {
+			p.pos++
+		}
+		n, err := strconv.ParseUint(string(p.src[digitsStart:p.pos]), 16, 64)
+		if err != nil {
+			p.pos = start
+			return p.errorf("invalid hexadecimal number: %v", err)
+		}
+		p.emit(strconv.FormatUint(n, 10), start)
+		return nil
+	}
+
+	digitsStart := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if (c >= '0' && c <= '9') || c == '.' || c == 'e' || c == 'E' ||
+			((c == '+' || c == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E')) {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if p.pos == digitsStart {
+		return p.errorf("invalid character %s in numeric literal", p.quoteChar())
+	}
+
+	// .5 => 0.5 and 5. => 5.0
+	for i := digitsStart; i < p.pos; i++ {
+		c := p.src[i]
+		if c == '.' && i == digitsStart {
+			p.emitByte('0', i)
+		}
+		p.emitByte(c, i)
+		if c == '.' && (i+1 == p.pos || p.src[i+1] < '0' || p.src[i+1] > '9') {
+			p.emitByte('0', i)
+		}
+	}
+	return nil
+}
+
+func (p *relaxedJSONParser) readIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentifierStart(p.src[p.pos]) || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isHexDigits(b []byte) bool {
+	for _, c := range b {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return len(b) > 0
+}
+
+// setRelaxedJSONPath sets f at path of v, path consists of object keys and array indices
+func setRelaxedJSONPath(v reflect.Value, path []any, f float64) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if len(path) == 0 {
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(f)
+			return nil
+		case reflect.Interface:
+			if v.NumMethod() == 0 {
+				v.Set(reflect.ValueOf(f))
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot assign %v to %s", f, v.Type())
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("cannot find %v in nil", path[0])
+		}
+		// elements of interface are not settable, copy, update and set back
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := setRelaxedJSONPath(elem, path, f); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Map:
+		key, ok := path[0].(string)
+		if !ok {
+			return fmt.Errorf("cannot index %s with %v", v.Type(), path[0])
+		}
+		kv := reflect.New(v.Type().Key()).Elem()
+		if err := unsafeAssign(kv, reflect.ValueOf(key), &UnsafeAssignOptions{}); err != nil {
+			return fmt.Errorf("map key %q: %w", key, err)
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if ev := v.MapIndex(kv); ev.IsValid() {
+			elem.Set(ev)
+		}
+		if err := setRelaxedJSONPath(elem, path[1:], f); err != nil {
+			return err
+		}
+		v.SetMapIndex(kv, elem)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, ok := path[0].(int)
+		if !ok || i >= v.Len() {
+			return fmt.Errorf("cannot index %s with %v", v.Type(), path[0])
+		}
+		return setRelaxedJSONPath(v.Index(i), path[1:], f)
+	case reflect.Struct:
+		key, ok := path[0].(string)
+		if !ok {
+			return fmt.Errorf("cannot index %s with %v", v.Type(), path[0])
+		}
+		fv, found := findJSONField(v, key)
+		if !found {
+			// unknown fields are ignored by encoding/json
+			return nil
+		}
+		return setRelaxedJSONPath(fv, path[1:], f)
+	default:
+		return fmt.Errorf("cannot index %s with %v", v.Type(), path[0])
+	}
+}
+
+// findJSONField finds the field of v decoded from key in the same way as encoding/json:
+// exact match of json tag or field name is preferred over case-insensitive match
+func findJSONField(v reflect.Value, key string) (reflect.Value, bool) {
+	var fold reflect.Value
+	for _, f := range structFields(v.Type(), "json", nil) {
+		if f.name != key && (fold.IsValid() || !strings.EqualFold(f.name, key)) {
+			continue
+		}
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			// nil embedded pointer
+			continue
+		}
+		if f.name == key {
+			return fv, true
+		}
+		fold = fv
+	}
+	return fold, fold.IsValid()
+}
@@ -0,0 +1,142 @@
+package conv
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFromRelaxedJSON(t *testing.T) {
+	type Server struct {
+		Host    string   `json:"host"`
+		Port    int      `json:"port"`
+		Weight  float64  `json:"weight"`
+		Limit   float64  `json:"limit"`
+		Tags    []string `json:"tags"`
+		Enabled bool
+	}
+
+	type Config struct {
+		Name    string             `json:"name"`
+		Servers []Server           `json:"servers"`
+		Mask    uint32             `json:"mask"`
+		Ratios  map[string]float64 `json:"ratios"`
+		Extra   any                `json:"extra"`
+	}
+
+	t.Run("Good", func(t *testing.T) {
+		data := `
+// config for the cluster
+{
+	name: 'prod "east"', /* inline
+	comment */
+	servers: [
+		{
+			host: "10.0.0.1",
+			port: +8080,
+			weight: .5,
+			limit: Infinity,
+			tags: ['a', 'b\'c',],
+			enabled: true,
+		},
+	],
+	mask: 0xFFFF,
+	ratios: {x: -Infinity, y: 5.},
+	"extra": [NaN, {n: 1}],
+}
+`
+		var c Config
+		if err := FromRelaxedJSON([]byte(data), &c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Name != `prod "east"` || c.Mask != 0xFFFF || len(c.Servers) != 1 {
+			t.Fatalf("%#v", c)
+		}
+		s := c.Servers[0]
+		if s.Host != "10.0.0.1" || s.Port != 8080 || s.Weight != 0.5 || !math.IsInf(s.Limit, 1) || !s.Enabled {
+			t.Fatalf("%#v", s)
+		}
+		if diff := diffSlice([]string{"a", "b'c"}, s.Tags); diff != "" {
+			t.Fatal(diff)
+		}
+		if !math.IsInf(c.Ratios["x"], -1) || c.Ratios["y"] != 5 {
+			t.Fatal(c.Ratios)
+		}
+		extra := c.Extra.([]any)
+		if !math.IsNaN(extra[0].(float64)) || extra[1].(map[string]any)["n"] != 1.0 {
+			t.Fatal(extra)
+		}
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		var v, expected any
+		data := `{"a":[1,2.5e3,"é\n"],"b":null}`
+		if err := FromRelaxedJSON([]byte(data), &v); err != nil {
+			t.Fatal(err)
+		}
+		_ = json.Unmarshal([]byte(data), &expected)
+		if MustToJSONString(v) != MustToJSONString(expected) {
+			t.Fatal(v)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		cases := []struct {
+			Data   string
+			Line   int
+			Column int
+		}{
+			{"{\n  a: 1\n  b: 2\n}", 3, 3},
+			{"{a: 1,, }", 1, 7},
+			{"[1, 2", 1, 6},
+			{"{a: 'x\n'}", 1, 7},
+			{"/* open", 1, 1},
+			{"{a: undefined}", 1, 5},
+			{"[1] 2", 1, 5},
+			{"{é: 1}", 1, 2},
+			{"[0x]", 1, 2},
+			{"[1.2.3]", 1, 5},
+		}
+		for _, c := range cases {
+			var v any
+			err := FromRelaxedJSON([]byte(c.Data), &v)
+			var syntaxErr *RelaxedJSONSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Errorf("%q: expect syntax error, got %v", c.Data, err)
+				continue
+			}
+			if syntaxErr.Line != c.Line || syntaxErr.Column != c.Column {
+				t.Errorf("%q: expect %d:%d, got %v", c.Data, c.Line, c.Column, err)
+			}
+		}
+
+		var c Config
+		err := FromRelaxedJSON([]byte("{\n  servers: [{port: 'x'}]}"), &c)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || err.Error()[:17] != "line 2, column 22" {
+			t.Fatal(err)
+		}
+		if err = FromRelaxedJSON([]byte("{servers: [{port: NaN}]}"), &c); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		nested := func(depth int) []byte {
+			return []byte(strings.Repeat("[", depth) + strings.Repeat("]", depth))
+		}
+		var v any
+		if err := FromRelaxedJSON(nested(maxDecodeDepth), &v); err != nil {
+			t.Fatal(err)
+		}
+		var syntaxErr *RelaxedJSONSyntaxError
+		if err := FromRelaxedJSON(nested(maxDecodeDepth+1), &v); !errors.As(err, &syntaxErr) {
+			t.Fatal(err)
+		}
+		if err := FromRelaxedJSON([]byte(strings.Repeat("{a:", 20_000_000)), &v); !errors.As(err, &syntaxErr) {
+			t.Fatal(err)
+		}
+	})
+}
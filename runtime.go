@@ -115,6 +115,95 @@ func Dereference[T any](p *T) T {
 	return zero
 }
 
+// NilPolicy decides how DerefList handles nil pointers
+type NilPolicy int
+
+const (
+	// NilAsZero converts nil pointers to zero values
+	NilAsZero NilPolicy = iota
+	// NilSkip drops nil pointers
+	NilSkip
+)
+
+// DerefList dereferences pointers in a, nil pointers are handled according to policy
+func DerefList[T any](a []*T, policy NilPolicy) []T {
+	l := make([]T, 0, len(a))
+	for _, p := range a {
+		if p == nil && policy == NilSkip {
+			continue
+		}
+		l = append(l, Dereference(p))
+	}
+	return l
+}
+
+// DerefOr returns *p, or fallback if p is nil
+func DerefOr[T any](p *T, fallback T) T {
+	if p != nil {
+		return *p
+	}
+	return fallback
+}
+
+// Coalesce returns the first non-nil pointer, or nil if all are nil
+func Coalesce[T any](ptrs ...*T) *T {
+	for _, p := range ptrs {
+		if p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// PtrIfNonZero returns nil if v is zero, otherwise a pointer to v
+func PtrIfNonZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// EqualPtr reports whether a and b are both nil or point to equal values
+func EqualPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// PtrMap returns a map of pointers to copies of values in m
+func PtrMap[K comparable, V any](m map[K]V) map[K]*V {
+	if m == nil {
+		return nil
+	}
+	res := make(map[K]*V, len(m))
+	for k, v := range m {
+		v := v
+		res[k] = &v
+	}
+	return res
+}
+
+// DerefDeep dereferences i until a value of type T is found, it's the typed counterpart of Indirect
+// It returns false if i is nil, or a nil pointer is met, even if T is an interface or pointer type the nil pointer satisfies
+func DerefDeep[T any](i any) (T, bool) {
+	var zero T
+	for {
+		v := reflect.ValueOf(i)
+		if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+			return zero, false
+		}
+		if t, ok := i.(T); ok {
+			return t, true
+		}
+		if v.Kind() != reflect.Ptr {
+			return zero, false
+		}
+		i = v.Elem().Interface()
+	}
+}
+
 func PanicWithMessages(msgAndArgs ...any) {
 	n := len(msgAndArgs)
 	switch n {
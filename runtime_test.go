@@ -65,3 +65,83 @@ func diffKinds(expected, got reflect.Kind) string {
 	}
 	return ""
 }
+
+func TestDerefList(t *testing.T) {
+	a := []*int{Addr(1), nil, Addr(3)}
+	if diff := diffSlice([]int{1, 0, 3}, DerefList(a, NilAsZero)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 3}, DerefList(a, NilSkip)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestPointerHelpers(t *testing.T) {
+	if DerefOr(nil, 5) != 5 || DerefOr(Addr(1), 5) != 1 {
+		t.Fatal("DerefOr")
+	}
+
+	b := Addr("b")
+	if Coalesce(nil, b, Addr("c")) != b || Coalesce[string](nil, nil) != nil || Coalesce[int]() != nil {
+		t.Fatal("Coalesce")
+	}
+
+	if PtrIfNonZero(0) != nil || *PtrIfNonZero(2) != 2 || PtrIfNonZero(time.Time{}) != nil {
+		t.Fatal("PtrIfNonZero")
+	}
+
+	if !EqualPtr[int](nil, nil) || !EqualPtr(Addr(1), Addr(1)) || EqualPtr(Addr(1), nil) || EqualPtr(Addr(1), Addr(2)) {
+		t.Fatal("EqualPtr")
+	}
+
+	m := map[string]int{"a": 1, "b": 2}
+	pm := PtrMap(m)
+	if len(pm) != 2 || *pm["a"] != 1 || *pm["b"] != 2 {
+		t.Fatal(pm)
+	}
+	*pm["a"] = 10
+	if m["a"] != 1 || PtrMap[string, int](nil) != nil {
+		t.Fatal(m)
+	}
+}
+
+func TestDerefDeep(t *testing.T) {
+	n := 3
+	p := &n
+	pp := &p
+	cases := []struct {
+		Value any
+		OK    bool
+	}{
+		{3, true},
+		{p, true},
+		{pp, true},
+		{&pp, true},
+		{nil, false},
+		{(*int)(nil), false},
+		{"3", false},
+	}
+	for _, c := range cases {
+		v, ok := DerefDeep[int](c.Value)
+		if ok != c.OK || (ok && v != 3) {
+			t.Errorf("%#v: got %v, %v", c.Value, v, ok)
+		}
+	}
+
+	if v, ok := DerefDeep[*int](pp); !ok || v != p {
+		t.Fatal(v, ok)
+	}
+	if v, ok := DerefDeep[fmt.Stringer](Addr(time.Second)); !ok || v.String() != "1s" {
+		t.Fatal(v, ok)
+	}
+	if v, ok := DerefDeep[fmt.Stringer]((*time.Time)(nil)); ok || v != nil {
+		t.Fatal(v, ok)
+	}
+	if v, ok := DerefDeep[*int]((**int)(nil)); ok || v != nil {
+		t.Fatal(v, ok)
+	}
+	var np *int
+	if v, ok := DerefDeep[*int](&np); ok || v != nil {
+		t.Fatal(v, ok)
+	}
+}
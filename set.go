@@ -0,0 +1,253 @@
+package conv
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+// Set is a set of comparable elements
+type Set[E comparable] map[E]struct{}
+
+// NewSet creates a set of elems
+func NewSet[E comparable](elems ...E) Set[E] {
+	s := make(Set[E], len(elems))
+	s.Add(elems...)
+	return s
+}
+
+func (s Set[E]) Add(elems ...E) {
+	for _, e := range elems {
+		s[e] = struct{}{}
+	}
+}
+
+func (s Set[E]) Remove(elems ...E) {
+	for _, e := range elems {
+		delete(s, e)
+	}
+}
+
+func (s Set[E]) Has(e E) bool {
+	_, ok := s[e]
+	return ok
+}
+
+func (s Set[E]) Len() int {
+	return len(s)
+}
+
+// Clone returns a copy of s
+func (s Set[E]) Clone() Set[E] {
+	c := make(Set[E], len(s))
+	for e := range s {
+		c[e] = struct{}{}
+	}
+	return c
+}
+
+// Union returns a new set of elements in s or o
+func (s Set[E]) Union(o Set[E]) Set[E] {
+	res := make(Set[E], len(s)+len(o))
+	for e := range s {
+		res[e] = struct{}{}
+	}
+	for e := range o {
+		res[e] = struct{}{}
+	}
+	return res
+}
+
+// Intersect returns a new set of elements in both s and o
+func (s Set[E]) Intersect(o Set[E]) Set[E] {
+	small, large := s, o
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	res := make(Set[E])
+	for e := range small {
+		if large.Has(e) {
+			res[e] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Difference returns a new set of elements in s but not in o
+func (s Set[E]) Difference(o Set[E]) Set[E] {
+	res := make(Set[E])
+	for e := range s {
+		if !o.Has(e) {
+			res[e] = struct{}{}
+		}
+	}
+	return res
+}
+
+// SymmetricDifference returns a new set of elements in either s or o but not in both
+func (s Set[E]) SymmetricDifference(o Set[E]) Set[E] {
+	res := make(Set[E])
+	for e := range s {
+		if !o.Has(e) {
+			res[e] = struct{}{}
+		}
+	}
+	for e := range o {
+		if !s.Has(e) {
+			res[e] = struct{}{}
+		}
+	}
+	return res
+}
+
+// IsSubset reports whether all elements of s are in o
+func (s Set[E]) IsSubset(o Set[E]) bool {
+	if len(s) > len(o) {
+		return false
+	}
+	for e := range s {
+		if !o.Has(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether s and o have the same elements
+func (s Set[E]) Equal(o Set[E]) bool {
+	return len(s) == len(o) && s.IsSubset(o)
+}
+
+// Slice returns elements of s in unspecified order
+func (s Set[E]) Slice() []E {
+	l := make([]E, 0, len(s))
+	for e := range s {
+		l = append(l, e)
+	}
+	return l
+}
+
+// MarshalJSON encodes s as an array
+// Elements are sorted by their JSON encodings so that the output is deterministic
+func (s Set[E]) MarshalJSON() ([]byte, error) {
+	items := make([][]byte, 0, len(s))
+	for e := range s {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, b)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i], items[j]) < 0
+	})
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(items, []byte{','}))
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an array into s, duplicate elements are merged
+func (s *Set[E]) UnmarshalJSON(b []byte) error {
+	var l []E
+	if err := json.Unmarshal(b, &l); err != nil {
+		return err
+	}
+	if l == nil {
+		*s = nil
+		return nil
+	}
+	*s = NewSet(l...)
+	return nil
+}
+
+// SortedSlice returns elements of s in ascending order
+func SortedSlice[E Ordered](s Set[E]) []E {
+	l := s.Slice()
+	sort.Slice(l, func(i, j int) bool {
+		return l[i] < l[j]
+	})
+	return l
+}
+
+// SyncSet is a set which is safe for concurrent use
+type SyncSet[E comparable] struct {
+	mu sync.RWMutex
+	s  Set[E]
+}
+
+func NewSyncSet[E comparable](elems ...E) *SyncSet[E] {
+	return &SyncSet[E]{s: NewSet(elems...)}
+}
+
+func (s *SyncSet[E]) Add(elems ...E) {
+	s.mu.Lock()
+	if s.s == nil {
+		s.s = make(Set[E], len(elems))
+	}
+	s.s.Add(elems...)
+	s.mu.Unlock()
+}
+
+func (s *SyncSet[E]) Remove(elems ...E) {
+	s.mu.Lock()
+	s.s.Remove(elems...)
+	s.mu.Unlock()
+}
+
+func (s *SyncSet[E]) Has(e E) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Has(e)
+}
+
+func (s *SyncSet[E]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.s)
+}
+
+// Snapshot returns a copy of elements as Set
+func (s *SyncSet[E]) Snapshot() Set[E] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Clone()
+}
+
+// Slice returns elements in unspecified order
+func (s *SyncSet[E]) Slice() []E {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Slice()
+}
+
+// Range calls fn for each element until fn returns false
+// fn must not modify s
+func (s *SyncSet[E]) Range(fn func(e E) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for e := range s.s {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (s *SyncSet[E]) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.MarshalJSON()
+}
+
+func (s *SyncSet[E]) UnmarshalJSON(b []byte) error {
+	var set Set[E]
+	if err := set.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.s = set
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,106 @@
+package conv
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := NewSet(1, 2, 3, 3)
+	b := NewSet(3, 4)
+	if a.Len() != 3 || !a.Has(1) || a.Has(4) {
+		t.Fatal(a)
+	}
+
+	if diff := diffSlice([]int{1, 2, 3, 4}, SortedSlice(a.Union(b))); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{3}, SortedSlice(a.Intersect(b))); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 2}, SortedSlice(a.Difference(b))); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 2, 4}, SortedSlice(a.SymmetricDifference(b))); diff != "" {
+		t.Fatal(diff)
+	}
+	if !NewSet(1, 3).IsSubset(a) || b.IsSubset(a) || !NewSet[int]().IsSubset(nil) {
+		t.Fatal("IsSubset")
+	}
+
+	c := a.Clone()
+	c.Add(5)
+	c.Remove(1, 2)
+	if a.Has(5) || !c.Equal(NewSet(3, 5)) || a.Equal(c) {
+		t.Fatal(a, c)
+	}
+
+	var nilSet Set[string]
+	if nilSet.Has("a") || len(nilSet.Slice()) != 0 || nilSet.Union(NewSet("a")).Len() != 1 {
+		t.Fatal(nilSet)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	s := NewSet("b", "c", "a")
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `["a","b","c"]` {
+		t.Fatal(string(b))
+	}
+
+	var v struct {
+		Tags Set[string] `json:"tags"`
+		IDs  Set[int]    `json:"ids"`
+	}
+	if err = json.Unmarshal([]byte(`{"tags":["x","y","x"],"ids":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Tags.Equal(NewSet("x", "y")) || v.IDs != nil {
+		t.Fatal(v)
+	}
+	if err = json.Unmarshal([]byte(`{"tags":"x"}`), &v); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestSyncSet(t *testing.T) {
+	s := NewSyncSet[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Add(i*100 + j)
+				s.Has(j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 800 {
+		t.Fatal(s.Len())
+	}
+
+	s.Remove(0)
+	n := 0
+	s.Range(func(e int) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 || s.Has(0) || len(s.Slice()) != 799 || s.Snapshot().Len() != 799 {
+		t.Fatal(n)
+	}
+
+	var zero SyncSet[string]
+	if err := json.Unmarshal([]byte(`["a"]`), &zero); err != nil || !zero.Has("a") {
+		t.Fatal(err)
+	}
+	zero.Add("b")
+	if b, err := json.Marshal(&zero); err != nil || string(b) != `["a","b"]` {
+		t.Fatal(string(b), err)
+	}
+}
@@ -1,5 +1,7 @@
 package conv
 
+import "sort"
+
 func UniqueSlice[T comparable](a []T) []T {
 	m := make(map[T]struct{}, len(a))
 	l := make([]T, 0, len(a))
@@ -13,6 +15,9 @@ func UniqueSlice[T comparable](a []T) []T {
 	return l
 }
 
+// SliceToSet converts a to a map whose values are always true
+//
+// Deprecated: use NewSet, which takes less memory and provides set operations
 func SliceToSet[E comparable](a []E) map[E]bool {
 	m := make(map[E]bool)
 	for _, v := range a {
@@ -40,3 +45,243 @@ func TransformToSliceE[E1 any, E2 any](a []E1, transformer func(e E1) (E2, error
 	}
 	return res, nil
 }
+
+// Ordered is a constraint that permits any ordered type
+type Ordered interface {
+	Number | ~string
+}
+
+// Filter returns elements of a for which pred returns true
+func Filter[E any](a []E, pred func(e E) bool) []E {
+	var res []E
+	for _, e := range a {
+		if pred(e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Reduce folds elements of a into a single value, starting with init
+func Reduce[E any, R any](a []E, init R, fn func(acc R, e E) R) R {
+	acc := init
+	for _, e := range a {
+		acc = fn(acc, e)
+	}
+	return acc
+}
+
+// GroupBy groups elements of a by key, elements in each group keep their order in a
+func GroupBy[E any, K comparable](a []E, key func(e E) K) map[K][]E {
+	m := make(map[K][]E)
+	for _, e := range a {
+		k := key(e)
+		m[k] = append(m[k], e)
+	}
+	return m
+}
+
+// Partition splits a into elements for which pred returns true and the others
+func Partition[E any](a []E, pred func(e E) bool) (matched []E, unmatched []E) {
+	for _, e := range a {
+		if pred(e) {
+			matched = append(matched, e)
+		} else {
+			unmatched = append(unmatched, e)
+		}
+	}
+	return matched, unmatched
+}
+
+// Chunk splits a into chunks of size, the last chunk may be shorter
+// Chunks share the underlying array of a with capacity limited to their lengths, so appending to a chunk does not affect a
+func Chunk[E any](a []E, size int) [][]E {
+	if size <= 0 {
+		panic("conv: chunk size must be positive")
+	}
+	res := make([][]E, 0, (len(a)+size-1)/size)
+	for i := 0; i < len(a); i += size {
+		j := i + size
+		if j > len(a) {
+			j = len(a)
+		}
+		res = append(res, a[i:j:j])
+	}
+	return res
+}
+
+// Window returns all sliding windows of size over a, or nil if a is shorter than size
+// Windows share the underlying array of a with capacity limited to their lengths
+func Window[E any](a []E, size int) [][]E {
+	if size <= 0 {
+		panic("conv: window size must be positive")
+	}
+	if len(a) < size {
+		return nil
+	}
+	res := make([][]E, 0, len(a)-size+1)
+	for i := 0; i+size <= len(a); i++ {
+		res = append(res, a[i:i+size:i+size])
+	}
+	return res
+}
+
+// Pair is a tuple of two values
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs elements of a and b by index, the result is as long as the shorter one
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	res := make([]Pair[A, B], n)
+	for i := range res {
+		res[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return res
+}
+
+// Unzip splits pairs into first and second elements
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a := make([]A, len(pairs))
+	b := make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
+
+// FlatMap maps each element of a to a slice and concatenates the results
+func FlatMap[E any, R any](a []E, fn func(e E) []R) []R {
+	var res []R
+	for _, e := range a {
+		res = append(res, fn(e)...)
+	}
+	return res
+}
+
+// UniqueBy returns elements of a with distinct keys, the first element of each key is kept
+func UniqueBy[E any, K comparable](a []E, key func(e E) K) []E {
+	m := make(map[K]struct{}, len(a))
+	res := make([]E, 0, len(a))
+	for _, e := range a {
+		k := key(e)
+		if _, ok := m[k]; ok {
+			continue
+		}
+		m[k] = struct{}{}
+		res = append(res, e)
+	}
+	return res
+}
+
+// IndexBy maps elements of a by key, the last element of each key wins
+func IndexBy[E any, K comparable](a []E, key func(e E) K) map[K]E {
+	m := make(map[K]E, len(a))
+	for _, e := range a {
+		m[key(e)] = e
+	}
+	return m
+}
+
+// CountBy counts elements of a by key
+func CountBy[E any, K comparable](a []E, key func(e E) K) map[K]int {
+	m := make(map[K]int)
+	for _, e := range a {
+		m[key(e)]++
+	}
+	return m
+}
+
+// Intersect returns distinct elements of a which are also in b, in the order of a
+func Intersect[E comparable](a, b []E) []E {
+	inB := make(map[E]struct{}, len(b))
+	for _, e := range b {
+		inB[e] = struct{}{}
+	}
+	res := make([]E, 0)
+	for _, e := range a {
+		if _, ok := inB[e]; ok {
+			res = append(res, e)
+			// avoid duplicates
+			delete(inB, e)
+		}
+	}
+	return res
+}
+
+// Union returns distinct elements of a and b, in the order of appearance
+func Union[E comparable](a, b []E) []E {
+	m := make(map[E]struct{}, len(a)+len(b))
+	res := make([]E, 0, len(a)+len(b))
+	for _, l := range [2][]E{a, b} {
+		for _, e := range l {
+			if _, ok := m[e]; ok {
+				continue
+			}
+			m[e] = struct{}{}
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Difference returns distinct elements of a which are not in b, in the order of a
+func Difference[E comparable](a, b []E) []E {
+	seen := make(map[E]struct{}, len(a)+len(b))
+	for _, e := range b {
+		seen[e] = struct{}{}
+	}
+	res := make([]E, 0)
+	for _, e := range a {
+		if _, ok := seen[e]; ok {
+			continue
+		}
+		seen[e] = struct{}{}
+		res = append(res, e)
+	}
+	return res
+}
+
+// Comparator returns a negative number if a < b, zero if a == b, or a positive number if a > b
+type Comparator[E any] func(a, b E) int
+
+// Asc compares elements by key in ascending order
+func Asc[E any, K Ordered](key func(e E) K) Comparator[E] {
+	return func(a, b E) int {
+		ka, kb := key(a), key(b)
+		switch {
+		case ka < kb:
+			return -1
+		case ka > kb:
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// Desc compares elements by key in descending order
+func Desc[E any, K Ordered](key func(e E) K) Comparator[E] {
+	asc := Asc(key)
+	return func(a, b E) int {
+		return asc(b, a)
+	}
+}
+
+// SortBy sorts a in place by comparators, later comparators break ties of earlier ones
+// The sort is stable, so elements equal by all comparators keep their order
+func SortBy[E any](a []E, comparators ...Comparator[E]) {
+	sort.SliceStable(a, func(i, j int) bool {
+		for _, cmp := range comparators {
+			if c := cmp(a[i], a[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
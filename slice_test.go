@@ -0,0 +1,225 @@
+package conv
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type sliceTestUser struct {
+	Name string
+	Age  int
+	Team string
+}
+
+var sliceTestUsers = []sliceTestUser{
+	{"Tom", 30, "a"},
+	{"Jerry", 25, "b"},
+	{"Spike", 30, "a"},
+	{"Tyke", 5, "c"},
+	{"Butch", 25, "a"},
+}
+
+func TestFilterReduce(t *testing.T) {
+	even := Filter([]int{1, 2, 3, 4}, func(i int) bool { return i%2 == 0 })
+	if diff := diffSlice([]int{2, 4}, even); diff != "" {
+		t.Fatal(diff)
+	}
+	if l := Filter([]int(nil), func(i int) bool { return true }); len(l) != 0 {
+		t.Fatal(l)
+	}
+	large := make([]int, 10000)
+	large[0] = 1
+	if l := Filter(large, func(i int) bool { return i == 1 }); len(l) != 1 || cap(l) > 8 {
+		t.Fatal(len(l), cap(l))
+	}
+
+	sum := Reduce(sliceTestUsers, 0, func(acc int, u sliceTestUser) int { return acc + u.Age })
+	if sum != 115 {
+		t.Fatal(sum)
+	}
+
+	matched, unmatched := Partition([]int{1, 2, 3, 4, 5}, func(i int) bool { return i > 3 })
+	if diff := diffSlice([]int{4, 5}, matched); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 2, 3}, unmatched); diff != "" {
+		t.Fatal(diff)
+	}
+	if matched, _ = Partition(large, func(i int) bool { return i == 1 }); len(matched) != 1 || cap(matched) > 8 {
+		t.Fatal(len(matched), cap(matched))
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	teams := GroupBy(sliceTestUsers, func(u sliceTestUser) string { return u.Team })
+	if len(teams) != 3 || len(teams["a"]) != 3 || teams["a"][2].Name != "Butch" {
+		t.Fatal(teams)
+	}
+
+	byName := IndexBy(sliceTestUsers, func(u sliceTestUser) string { return u.Name })
+	if byName["Tyke"].Age != 5 {
+		t.Fatal(byName)
+	}
+	byAge := IndexBy(sliceTestUsers, func(u sliceTestUser) int { return u.Age })
+	if byAge[30].Name != "Spike" {
+		t.Fatal(byAge)
+	}
+
+	counts := CountBy(sliceTestUsers, func(u sliceTestUser) int { return u.Age })
+	if !reflect.DeepEqual(counts, map[int]int{30: 2, 25: 2, 5: 1}) {
+		t.Fatal(counts)
+	}
+
+	unique := UniqueBy(sliceTestUsers, func(u sliceTestUser) int { return u.Age })
+	names := TransformToSlice(unique, func(u sliceTestUser) string { return u.Name })
+	if diff := diffSlice([]string{"Tom", "Jerry", "Tyke"}, names); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	a := []int{1, 2, 3, 4, 5}
+	chunks := Chunk(a, 2)
+	if !reflect.DeepEqual(chunks, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Fatal(chunks)
+	}
+	chunks[0] = append(chunks[0], 100)
+	if a[2] != 3 {
+		t.Fatal("appending to a chunk should not overwrite the source")
+	}
+	if chunks = Chunk([]int{}, 3); len(chunks) != 0 {
+		t.Fatal(chunks)
+	}
+
+	windows := Window(a, 3)
+	if !reflect.DeepEqual(windows, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}) {
+		t.Fatal(windows)
+	}
+	if windows = Window(a, 6); windows != nil {
+		t.Fatal(windows)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("should panic")
+		}
+	}()
+	Chunk(a, 0)
+}
+
+func TestZip(t *testing.T) {
+	pairs := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	if !reflect.DeepEqual(pairs, []Pair[string, int]{{"a", 1}, {"b", 2}}) {
+		t.Fatal(pairs)
+	}
+	keys, values := Unzip(pairs)
+	if diff := diffSlice([]string{"a", "b"}, keys); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 2}, values); diff != "" {
+		t.Fatal(diff)
+	}
+
+	l := FlatMap([]int{1, 2, 3}, func(i int) []string {
+		return []string{strconv.Itoa(i), strconv.Itoa(i * 10)}
+	})
+	if diff := diffSlice([]string{"1", "10", "2", "20", "3", "30"}, l); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []int{1, 2, 2, 3, 4}
+	b := []int{4, 2, 5, 5}
+	if diff := diffSlice([]int{2, 4}, Intersect(a, b)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 2, 3, 4, 5}, Union(a, b)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]int{1, 3}, Difference(a, b)); diff != "" {
+		t.Fatal(diff)
+	}
+	if l := Intersect(a, nil); len(l) != 0 {
+		t.Fatal(l)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	users := append([]sliceTestUser(nil), sliceTestUsers...)
+	SortBy(users,
+		Desc(func(u sliceTestUser) int { return u.Age }),
+		Asc(func(u sliceTestUser) string { return u.Team }),
+	)
+	names := TransformToSlice(users, func(u sliceTestUser) string { return u.Name })
+	if diff := diffSlice([]string{"Tom", "Spike", "Butch", "Jerry", "Tyke"}, names); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// stable without comparators
+	SortBy(users)
+	names = TransformToSlice(users, func(u sliceTestUser) string { return u.Name })
+	if diff := diffSlice([]string{"Tom", "Spike", "Butch", "Jerry", "Tyke"}, names); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func benchmarkInts(n int) []int {
+	a := make([]int, n)
+	for i := range a {
+		a[i] = (i * 7919) % (n / 2)
+	}
+	return a
+}
+
+func BenchmarkFilter(b *testing.B) {
+	a := benchmarkInts(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Filter(a, func(e int) bool { return e%2 == 0 })
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	a := benchmarkInts(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GroupBy(a, func(e int) int { return e % 16 })
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	a := benchmarkInts(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Chunk(a, 100)
+	}
+}
+
+func BenchmarkUniqueBy(b *testing.B) {
+	a := benchmarkInts(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		UniqueBy(a, func(e int) int { return e })
+	}
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	a := benchmarkInts(10000)
+	c := benchmarkInts(5000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Intersect(a, c)
+	}
+}
+
+func BenchmarkSortBy(b *testing.B) {
+	a := benchmarkInts(10000)
+	l := make([]int, len(a))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(l, a)
+		SortBy(l, Asc(func(e int) int { return e % 100 }), Desc(func(e int) int { return e }))
+	}
+}
@@ -0,0 +1,288 @@
+package conv
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+	// sqlTimeLayouts are formats of time returned as text by drivers
+	sqlTimeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999-07",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+	}
+)
+
+// Null represents a value of type T which may be NULL
+// Scan converts driver values such as []byte, int64, float64 and time.Time to T with ToXxx converters,
+// so that T can be a named type, e.g. type Status string
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNull returns a valid Null of v
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// Ptr returns nil if n is NULL, otherwise a pointer to a copy of n.V
+func (n Null[T]) Ptr() *T {
+	if !n.Valid {
+		return nil
+	}
+	v := n.V
+	return &v
+}
+
+// Scan implements sql.Scanner
+func (n *Null[T]) Scan(src any) error {
+	if src == nil {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	if err := scanValue(reflect.ValueOf(&n.V).Elem(), src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		var zero T
+		n.V, n.Valid = zero, false
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// scanValue converts driver value src and sets it to dst
+func scanValue(dst reflect.Value, src any) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return scanValue(dst.Elem(), src)
+	}
+
+	if s, ok := dst.Addr().Interface().(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dst.Type()) {
+		if b, ok := src.([]byte); ok {
+			// drivers may reuse the buffer
+			src = append([]byte(nil), b...)
+		}
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	}
+
+	text, isText := src.(string)
+	if b, ok := src.([]byte); ok {
+		text, isText = string(b), true
+	}
+
+	switch {
+	case dst.Type() == timeType:
+		if !isText {
+			return fmt.Errorf("cannot scan %T into time.Time", src)
+		}
+		var err error
+		for _, layout := range sqlTimeLayouts {
+			var t time.Time
+			if t, err = time.Parse(layout, text); err == nil {
+				dst.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("parse time: %w", err)
+	case dst.Kind() == reflect.String:
+		s, err := ToString(src)
+		if err != nil {
+			return err
+		}
+		dst.SetString(s)
+	case dst.Kind() == reflect.Bool:
+		b, err := ToBool(src)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case IsIntValue(dst):
+		i, err := ToInt64(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowInt(i) {
+			return strconv.ErrRange
+		}
+		dst.SetInt(i)
+	case IsUintValue(dst):
+		i, err := ToUint64(src)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowUint(i) {
+			return strconv.ErrRange
+		}
+		dst.SetUint(i)
+	case IsFloatValue(dst):
+		f, err := ToFloat64(src)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 && isText:
+		dst.SetBytes([]byte(text))
+	case isText && dst.Addr().Type().Implements(textUnmarshaler):
+		return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	case isText && (dst.Kind() == reflect.Struct || dst.Kind() == reflect.Map || dst.Kind() == reflect.Slice):
+		// json columns
+		return json.Unmarshal([]byte(text), dst.Addr().Interface())
+	case sv.Type().ConvertibleTo(dst.Type()):
+		dst.Set(sv.Convert(dst.Type()))
+	default:
+		return fmt.Errorf("cannot scan %T into %v", src, dst.Type())
+	}
+	return nil
+}
+
+// ScanOptions controls how columns are mapped to struct fields
+type ScanOptions struct {
+	// TagName is the key of struct tags, "db" by default
+	TagName string
+
+	// FieldNameMatcher matches a column to the name of a field without tag
+	// By default, names are compared case-insensitively ignoring '_' and '-', e.g. user_id matches UserID
+	FieldNameMatcher FieldNameMatcher
+}
+
+// columnScanner scans a column into a field
+type columnScanner struct {
+	dst    reflect.Value
+	column string
+}
+
+func (s *columnScanner) Scan(src any) error {
+	if err := scanValue(s.dst, src); err != nil {
+		return fmt.Errorf("column %s: %w", s.column, err)
+	}
+	return nil
+}
+
+// ScanRowsInto scans all rows into values of T and closes rows
+// T can be a struct, a pointer to struct, or any type of a single column.
+// Columns without matched fields are ignored
+func ScanRowsInto[T any](rows *sql.Rows, optFns ...func(options *ScanOptions)) ([]T, error) {
+	defer rows.Close()
+	options := &ScanOptions{
+		TagName:          "db",
+		FieldNameMatcher: looseFieldNameMatcher{},
+	}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	isStruct := st.Kind() == reflect.Struct && !isSQLLeafType(st)
+
+	var fields [][]int
+	if isStruct {
+		all := structFields(st, options.TagName, isSQLLeafType)
+		fields = make([][]int, len(columns))
+		for i, c := range columns {
+			if f := findTagField(all, c, options.FieldNameMatcher); f != nil {
+				fields[i] = f.index
+			}
+		}
+	} else if len(columns) != 1 {
+		return nil, fmt.Errorf("cannot scan %d columns into %v", len(columns), t)
+	}
+
+	var res []T
+	for rows.Next() {
+		var v T
+		rv := reflect.ValueOf(&v).Elem()
+		dest := make([]any, len(columns))
+		if isStruct {
+			if rv.Kind() == reflect.Ptr {
+				rv.Set(reflect.New(st))
+				rv = rv.Elem()
+			}
+			for i := range columns {
+				if fields[i] == nil {
+					dest[i] = new(any)
+					continue
+				}
+				fv, err := fieldByIndexAlloc(rv, fields[i])
+				if err != nil {
+					return nil, err
+				}
+				dest[i] = &columnScanner{dst: fv, column: columns[i]}
+			}
+		} else {
+			dest[0] = &columnScanner{dst: rv, column: columns[0]}
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// isSQLLeafType reports whether a struct of t is scanned as a whole instead of by fields
+func isSQLLeafType(t reflect.Type) bool {
+	return t == timeType || reflect.PtrTo(t).Implements(scannerType)
+}
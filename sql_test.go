@@ -0,0 +1,272 @@
+package conv
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeSQLDriver returns the rows registered with the query text
+type fakeSQLDriver struct {
+}
+
+type fakeSQLResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var fakeSQLResults = map[string]*fakeSQLResult{}
+
+func (d fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	return fakeSQLConn{}, nil
+}
+
+type fakeSQLConn struct {
+}
+
+func (c fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	r, ok := fakeSQLResults[query]
+	if !ok {
+		return nil, errors.New("unknown query")
+	}
+	return fakeSQLStmt{result: r}, nil
+}
+
+func (c fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type fakeSQLStmt struct {
+	result *fakeSQLResult
+}
+
+func (s fakeSQLStmt) Close() error {
+	return nil
+}
+
+func (s fakeSQLStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeSQLRows{result: s.result}, nil
+}
+
+type fakeSQLRows struct {
+	result *fakeSQLResult
+	i      int
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	return r.result.columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.i])
+	r.i++
+	return nil
+}
+
+func openFakeSQL(t *testing.T) *sql.DB {
+	db, err := sql.Open("conv_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
+func init() {
+	sql.Register("conv_fake", fakeSQLDriver{})
+}
+
+type sqlTestStatus string
+
+type sqlTestBase struct {
+	ID int64 `db:"id"`
+}
+
+type sqlTestUser struct {
+	sqlTestBase
+	Name      string
+	Status    sqlTestStatus       `db:"status"`
+	Age       Null[uint8]         `db:"age"`
+	Nickname  *string             `db:"nickname"`
+	CreatedAt time.Time           `db:"created_at"`
+	Profile   map[string]any      `db:"profile"`
+	Score     Null[float32]       `db:"score"`
+	Ignored   string              `db:"-"`
+	Flags     Null[sqlTestStatus] `db:"flags"`
+}
+
+func TestNull(t *testing.T) {
+	t.Run("Scan", func(t *testing.T) {
+		var n Null[sqlTestStatus]
+		if err := n.Scan([]byte("active")); err != nil || !n.Valid || n.V != "active" {
+			t.Fatal(n, err)
+		}
+		if err := n.Scan(nil); err != nil || n.Valid || n.V != "" {
+			t.Fatal(n, err)
+		}
+
+		var i Null[int16]
+		if err := i.Scan(int64(7)); err != nil || i.V != 7 {
+			t.Fatal(i, err)
+		}
+		if err := i.Scan([]byte("12")); err != nil || i.V != 12 {
+			t.Fatal(i, err)
+		}
+		if err := i.Scan(int64(70000)); !errors.Is(err, strconv.ErrRange) {
+			t.Fatal(err)
+		}
+
+		var tm Null[time.Time]
+		if err := tm.Scan([]byte("2024-01-02 03:04:05")); err != nil || tm.V.Hour() != 3 {
+			t.Fatal(tm, err)
+		}
+		if err := tm.Scan(int64(1)); err == nil {
+			t.Fatal("should fail")
+		}
+
+		var b Null[bool]
+		if err := b.Scan(int64(1)); err != nil || !b.V {
+			t.Fatal(b, err)
+		}
+	})
+
+	t.Run("Value", func(t *testing.T) {
+		v, err := NewNull[sqlTestStatus]("active").Value()
+		if err != nil || v != "active" {
+			t.Fatal(v, err)
+		}
+		v, err = NewNull[uint8](3).Value()
+		if err != nil || v != int64(3) {
+			t.Fatal(v, err)
+		}
+		v, err = Null[int]{}.Value()
+		if err != nil || v != nil {
+			t.Fatal(v, err)
+		}
+		if p := (Null[int]{}).Ptr(); p != nil {
+			t.Fatal(p)
+		}
+		if p := NewNull(1).Ptr(); p == nil || *p != 1 {
+			t.Fatal(p)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var v struct {
+			A Null[int]    `json:"a"`
+			B Null[string] `json:"b"`
+		}
+		if err := json.Unmarshal([]byte(`{"a":null,"b":"x"}`), &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.A.Valid || !v.B.Valid || v.B.V != "x" {
+			t.Fatal(v)
+		}
+		if s := MustToJSONString(v); s != `{"a":null,"b":"x"}` {
+			t.Fatal(s)
+		}
+	})
+}
+
+func TestScanRowsInto(t *testing.T) {
+	db := openFakeSQL(t)
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fakeSQLResults["users"] = &fakeSQLResult{
+		columns: []string{"id", "name", "status", "age", "nickname", "created_at", "profile", "score", "flags", "extra"},
+		rows: [][]driver.Value{
+			{int64(1), []byte("Tom"), []byte("active"), int64(30), []byte("tommy"), created, []byte(`{"lang":"en"}`), 9.5, nil, "x"},
+			{int64(2), "Jerry", "blocked", nil, nil, []byte("2024-01-02T03:04:05Z"), nil, nil, "f", nil},
+		},
+	}
+	fakeSQLResults["ids"] = &fakeSQLResult{
+		columns: []string{"id"},
+		rows:    [][]driver.Value{{int64(1)}, {[]byte("2")}},
+	}
+	fakeSQLResults["bad"] = &fakeSQLResult{
+		columns: []string{"id", "age"},
+		rows:    [][]driver.Value{{int64(1), int64(300)}},
+	}
+
+	t.Run("Struct", func(t *testing.T) {
+		rows, err := db.Query("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		users, err := ScanRowsInto[*sqlTestUser](rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 2 {
+			t.Fatal(users)
+		}
+		u := users[0]
+		if u.ID != 1 || u.Name != "Tom" || u.Status != "active" || u.Age != NewNull[uint8](30) || u.Nickname == nil || *u.Nickname != "tommy" {
+			t.Fatalf("%#v", u)
+		}
+		if !u.CreatedAt.Equal(created) || u.Profile["lang"] != "en" || u.Score.V != 9.5 || u.Flags.Valid {
+			t.Fatalf("%#v", u)
+		}
+		u = users[1]
+		if u.Name != "Jerry" || u.Age.Valid || u.Nickname != nil || !u.CreatedAt.Equal(created) || u.Flags.V != "f" {
+			t.Fatalf("%#v", u)
+		}
+	})
+
+	t.Run("Column", func(t *testing.T) {
+		rows, err := db.Query("ids")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, err := ScanRowsInto[int](rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := diffSlice([]int{1, 2}, ids); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		rows, err := db.Query("bad")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ScanRowsInto[sqlTestUser](rows); !errors.Is(err, strconv.ErrRange) {
+			t.Fatal(err)
+		}
+
+		rows, err = db.Query("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ScanRowsInto[string](rows); err == nil {
+			t.Fatal("should fail")
+		}
+	})
+}
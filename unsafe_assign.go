@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
 )
 
 type FieldNameMatcher interface {
@@ -23,9 +24,23 @@ func (f fieldNameEqual) MatchFieldName(srcName, dstName string) bool {
 
 type UnsafeAssignOptions struct {
 	FieldNameMatcher FieldNameMatcher
+
+	// BytesEncoding is used to decode a string assigned to []byte or [N]byte
+	BytesEncoding Encoding
+
+	// TagName is the key of struct tags, e.g. json
+	// If a field has a tag name, a source key must be equal to the tag name instead of matching the field name.
+	// Fields tagged "-" are skipped.
+	TagName string
+
+	// Overwrite makes dst mirror src, which decoders rely on:
+	// nil source values reset destinations to zero values, and structs of the same type are copied as a whole.
+	// By default, nil source values are skipped so that src can be applied to dst as a partial update
+	Overwrite bool
 }
 
 // UnsafeAssign fill src underlying value and fields with dst
+// Nil source values and None options are skipped unless Overwrite is set, and a nil interface destination is set to the source value directly if assignable
 func UnsafeAssign(dst any, src any, optFns ...func(options *UnsafeAssignOptions)) error {
 	options := &UnsafeAssignOptions{}
 	for _, fn := range optFns {
@@ -66,7 +81,33 @@ func UnsafeAssign(dst any, src any, optFns ...func(options *UnsafeAssignOptions)
 // dst is valid value or pointer to value
 func unsafeAssign(dst reflect.Value, src reflect.Value, options *UnsafeAssignOptions) error {
 	src = IndirectReadableValue(src)
+	if src.IsValid() && src.CanInterface() {
+		if o, ok := src.Interface().(optionValuer); ok {
+			v, some := o.optionValue()
+			if !some {
+				// None only resets Option destinations
+				if isOptionValue(dst) && dst.CanSet() {
+					dst.Set(reflect.Zero(dst.Type()))
+				}
+				return nil
+			}
+			src = IndirectReadableValue(reflect.ValueOf(v))
+		}
+	}
+	if !src.IsValid() || ((src.Kind() == reflect.Ptr || src.Kind() == reflect.Interface) && src.IsNil()) {
+		if options.Overwrite && dst.CanSet() {
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return nil
+	}
 	dv := IndirectWritableValue(dst, true)
+	if dv.CanAddr() && dv.Addr().CanInterface() {
+		if o, ok := dv.Addr().Interface().(optionAssigner); ok {
+			return o.assignOption(func(v reflect.Value) error {
+				return unsafeAssign(v, src, options)
+			})
+		}
+	}
 	switch dv.Kind() {
 	case reflect.Bool:
 		b, err := ToBool(src.Interface())
@@ -81,6 +122,14 @@ func unsafeAssign(dst reflect.Value, src reflect.Value, options *UnsafeAssignOpt
 		}
 		dv.SetString(s)
 	case reflect.Slice:
+		if dv.Type().Elem().Kind() == reflect.Uint8 && src.Kind() == reflect.String {
+			b, err := DecodeBytes(src.String(), options.BytesEncoding)
+			if err != nil {
+				return fmt.Errorf("decode %v: %w", options.BytesEncoding, err)
+			}
+			dv.SetBytes(b)
+			return nil
+		}
 		if src.Kind() != reflect.Slice {
 			return errors.New("source value is not slice")
 		}
@@ -92,6 +141,26 @@ func unsafeAssign(dst reflect.Value, src reflect.Value, options *UnsafeAssignOpt
 			}
 		}
 		dv.Set(l)
+	case reflect.Array:
+		if dv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unknown kind=%v", dv.Kind())
+		}
+		var b []byte
+		switch {
+		case src.Kind() == reflect.String:
+			var err error
+			b, err = DecodeBytes(src.String(), options.BytesEncoding)
+			if err != nil {
+				return fmt.Errorf("decode %v: %w", options.BytesEncoding, err)
+			}
+		case src.Kind() == reflect.Slice && src.Type().Elem().Kind() == reflect.Uint8:
+			b = src.Bytes()
+		default:
+			return fmt.Errorf("cannot assign %v to %v", src.Type(), dv.Type())
+		}
+		if err := fillByteArray(dv, b, &ByteArrayOptions{}); err != nil {
+			return err
+		}
 	case reflect.Map:
 		if src.Kind() != reflect.Map {
 			return fmt.Errorf("cannot assign %v to map", src.Kind())
@@ -109,6 +178,13 @@ func unsafeAssign(dst reflect.Value, src reflect.Value, options *UnsafeAssignOpt
 		return nil
 	case reflect.Interface:
 		// if i is a pointer to an interface, then ValueOf(i).Elem().Kind() is reflect.Interface
+		if dv.IsNil() {
+			if !src.Type().AssignableTo(dv.Type()) {
+				return fmt.Errorf("cannot assign %v to %v", src.Type(), dv.Type())
+			}
+			dv.Set(src)
+			return nil
+		}
 		pv := reflect.New(dv.Elem().Type())
 		if err := unsafeAssign(pv.Elem(), src, options); err != nil {
 			return fmt.Errorf("cannot assign to interface(%v): %w", dv.Elem().Kind(), err)
@@ -149,6 +225,10 @@ func valueToStruct(dst reflect.Value, src reflect.Value, options *UnsafeAssignOp
 		}
 		return nil
 	case reflect.Struct:
+		if options.Overwrite && src.Type() == dst.Type() {
+			dst.Set(src)
+			return nil
+		}
 		err := structToStruct(dst, src, options)
 		if err != nil {
 			return fmt.Errorf("structToStruct: %w", err)
@@ -265,7 +345,7 @@ func mapToStruct(dst reflect.Value, src reflect.Value, options *UnsafeAssignOpti
 		}
 
 		for _, key := range src.MapKeys() {
-			if !options.FieldNameMatcher.MatchFieldName(key.String(), ft.Name) {
+			if !matchFieldName(options, key.String(), ft) {
 				continue
 			}
 
@@ -289,6 +369,107 @@ func mapToStruct(dst reflect.Value, src reflect.Value, options *UnsafeAssignOpti
 	return nil
 }
 
+// matchFieldName reports whether srcName matches the tag name of field if any, otherwise the field name
+func matchFieldName(options *UnsafeAssignOptions, srcName string, field reflect.StructField) bool {
+	if options.TagName != "" {
+		name, _, skip := lookupFieldTag(field, options.TagName)
+		if skip {
+			return false
+		}
+		if name != "" {
+			return name == srcName
+		}
+	}
+	return options.FieldNameMatcher.MatchFieldName(srcName, field.Name)
+}
+
+// lookupFieldTag parses tag `key:"name,opt1,opt2"` of field
+// skip is true if the tag is "-"
+func lookupFieldTag(field reflect.StructField, key string) (name string, opts []string, skip bool) {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return "", nil, false
+	}
+	if tag == "-" {
+		return "", nil, true
+	}
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:], false
+}
+
+// tagField is a field found by structFields
+type tagField struct {
+	name   string
+	tagged bool
+	opts   []string
+	tag    reflect.StructTag
+	index  []int
+	typ    reflect.Type
+}
+
+// structFields lists exported fields of t in order
+// Fields of embedded structs are flattened unless isLeaf, which may be nil, reports true for the struct type.
+// Unexported embedded structs are flattened as well because their exported fields are still settable,
+// though a nil pointer to an unexported struct cannot be allocated by fieldByIndexAlloc.
+func structFields(t reflect.Type, tagName string, isLeaf func(t reflect.Type) bool) []tagField {
+	return appendStructFields(nil, t, tagName, isLeaf, nil)
+}
+
+func appendStructFields(fields []tagField, t reflect.Type, tagName string, isLeaf func(t reflect.Type) bool, index []int) []tagField {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, skip := lookupFieldTag(field, tagName)
+		if skip {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct && (isLeaf == nil || !isLeaf(ft)) {
+			fields = appendStructFields(fields, ft, tagName, isLeaf, fieldIndex)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		f := tagField{name: name, tagged: true, opts: opts, tag: field.Tag, index: fieldIndex, typ: field.Type}
+		if name == "" {
+			f.name, f.tagged = field.Name, false
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// findTagField returns the field whose tag name equals name, or whose field name matches name if untagged
+func findTagField(fields []tagField, name string, matcher FieldNameMatcher) *tagField {
+	for i, f := range fields {
+		if (f.tagged && f.name == name) || (!f.tagged && matcher.MatchFieldName(name, f.name)) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex but allocates nil embedded pointers
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
 func structToStruct(dst reflect.Value, src reflect.Value, options *UnsafeAssignOptions) error {
 	for i := 0; i < dst.NumField(); i++ {
 		fv := dst.Field(i)
@@ -2,6 +2,8 @@ package conv
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -176,3 +178,137 @@ func TestUnsafeAssignMap(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestUnsafeAssignNil(t *testing.T) {
+	type Inner struct {
+		X int
+	}
+
+	type Patch struct {
+		Name  *string
+		Age   *int
+		Inner *Inner
+	}
+
+	type Target struct {
+		Name  string
+		Age   int
+		Inner Inner
+	}
+
+	t.Run("Skip", func(t *testing.T) {
+		target := Target{Name: "x", Age: 5, Inner: Inner{X: 7}}
+		if err := UnsafeAssign(&target, Patch{Name: Pointer("Tom")}); err != nil {
+			t.Fatal(err)
+		}
+		if target != (Target{Name: "Tom", Age: 5, Inner: Inner{X: 7}}) {
+			t.Fatalf("%+v", target)
+		}
+
+		n := 1
+		p := &n
+		if err := unsafeAssign(reflect.ValueOf(&p).Elem(), reflect.ValueOf((*int)(nil)), &UnsafeAssignOptions{}); err != nil || p != &n {
+			t.Fatal(p, err)
+		}
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		options := &UnsafeAssignOptions{FieldNameMatcher: fieldNameEqual{}, Overwrite: true}
+
+		var ptrs []*string
+		if err := unsafeAssign(reflect.ValueOf(&ptrs).Elem(), reflect.ValueOf([]any{nil, "a"}), options); err != nil {
+			t.Fatal(err)
+		}
+		if len(ptrs) != 2 || ptrs[0] != nil || *ptrs[1] != "a" {
+			t.Fatal(ptrs)
+		}
+
+		target := Target{Name: "x", Age: 5, Inner: Inner{X: 7}}
+		err := unsafeAssign(reflect.ValueOf(&target).Elem(), reflect.ValueOf(Patch{Name: Pointer("Tom")}), options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if target != (Target{Name: "Tom"}) {
+			t.Fatalf("%+v", target)
+		}
+
+		// nil map values are skipped for struct fields even if Overwrite is set
+		item := Target{Name: "x"}
+		if err = unsafeAssign(reflect.ValueOf(&item).Elem(), reflect.ValueOf(map[string]any{"Name": nil}), options); err != nil || item.Name != "x" {
+			t.Fatal(item, err)
+		}
+	})
+}
+
+func TestUnsafeAssignInterface(t *testing.T) {
+	options := &UnsafeAssignOptions{FieldNameMatcher: fieldNameEqual{}}
+
+	var i any
+	if err := unsafeAssign(reflect.ValueOf(&i).Elem(), reflect.ValueOf([]int{1}), options); err != nil {
+		t.Fatal(err)
+	}
+	if l, ok := i.([]int); !ok || len(l) != 1 || l[0] != 1 {
+		t.Fatalf("%#v", i)
+	}
+
+	var s fmt.Stringer
+	if err := unsafeAssign(reflect.ValueOf(&s).Elem(), reflect.ValueOf(time.Second), options); err != nil || s != time.Second {
+		t.Fatal(s, err)
+	}
+	s = nil
+	if err := unsafeAssign(reflect.ValueOf(&s).Elem(), reflect.ValueOf(1), options); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestUnsafeAssignSameStruct(t *testing.T) {
+	type Event struct {
+		At time.Time
+	}
+
+	at := time.Date(2020, 12, 6, 12, 46, 15, 134526000, time.FixedZone("EST", -5*3600))
+	var e Event
+	err := unsafeAssign(reflect.ValueOf(&e).Elem(), reflect.ValueOf(map[string]any{"At": at}), &UnsafeAssignOptions{
+		FieldNameMatcher: fieldNameEqual{},
+		Overwrite:        true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.At.Equal(at) || e.At.Location() != at.Location() {
+		t.Fatal(e.At)
+	}
+
+	// structs of the same type are assigned field by field by default, so nil fields are kept
+	type Item struct {
+		Name *string
+		Age  int
+	}
+	item := Item{Name: Pointer("x"), Age: 1}
+	err = unsafeAssign(reflect.ValueOf(&item).Elem(), reflect.ValueOf(Item{Age: 2}), &UnsafeAssignOptions{
+		FieldNameMatcher: fieldNameEqual{},
+	})
+	if err != nil || item.Name == nil || *item.Name != "x" || item.Age != 2 {
+		t.Fatal(item, err)
+	}
+}
+
+func TestUnsafeAssignTagName(t *testing.T) {
+	type User struct {
+		Name   string `db:"full_name"`
+		Secret string `db:"-"`
+		Age    int
+	}
+
+	var u User
+	err := unsafeAssign(reflect.ValueOf(&u).Elem(), reflect.ValueOf(map[string]any{"full_name": "Tom", "Name": "x", "Secret": "s", "Age": 3}), &UnsafeAssignOptions{
+		FieldNameMatcher: fieldNameEqual{},
+		TagName:          "db",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "Tom" || u.Secret != "" || u.Age != 3 {
+		t.Fatalf("%#v", u)
+	}
+}
@@ -0,0 +1,495 @@
+package conv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ValuesOptions controls how url.Values are mapped to and from structs
+type ValuesOptions struct {
+	// TagName is the key of struct tags, "form" by default
+	TagName string
+
+	// FieldNameMatcher matches a key to the name of a field without tag
+	// By default, names are compared case-insensitively ignoring '_' and '-', e.g. user_id matches UserID
+	FieldNameMatcher FieldNameMatcher
+
+	// TimeLayout is used to parse and format time.Time, time.RFC3339 by default
+	// Unix seconds are also accepted when decoding
+	TimeLayout string
+
+	// MaxIndex is the max index in keys like list[9] when decoding, 1000 by default
+	// Larger indices are rejected because decoding allocates a slice as long as the largest index
+	MaxIndex int
+}
+
+func newValuesOptions(optFns []func(options *ValuesOptions)) *ValuesOptions {
+	options := &ValuesOptions{
+		TagName:          "form",
+		FieldNameMatcher: looseFieldNameMatcher{},
+		TimeLayout:       time.RFC3339,
+		MaxIndex:         1000,
+	}
+	for _, fn := range optFns {
+		fn(options)
+	}
+	return options
+}
+
+// looseFieldNameMatcher matches names case-insensitively ignoring '_' and '-'
+type looseFieldNameMatcher struct {
+}
+
+func (m looseFieldNameMatcher) MatchFieldName(srcName, dstName string) bool {
+	return strings.EqualFold(removeNameSeparators(srcName), removeNameSeparators(dstName))
+}
+
+func removeNameSeparators(s string) string {
+	if !strings.ContainsAny(s, "_-") {
+		return s
+	}
+	return strings.NewReplacer("_", "", "-", "").Replace(s)
+}
+
+// valuesNode is the tree built from bracketed or dotted keys, e.g. a[b][c]=1 or list[0].name=x
+type valuesNode struct {
+	values   []string
+	children map[string]*valuesNode
+}
+
+func (n *valuesNode) child(key string) *valuesNode {
+	if n.children == nil {
+		n.children = make(map[string]*valuesNode)
+	}
+	c := n.children[key]
+	if c == nil {
+		c = &valuesNode{}
+		n.children[key] = c
+	}
+	return c
+}
+
+// splitValuesKey splits a[b][c] or list[0].name into segments, empty brackets like tags[] are ignored
+func splitValuesKey(key string) []string {
+	var segments []string
+	for key != "" {
+		i := strings.IndexAny(key, "[.")
+		if i < 0 {
+			segments = append(segments, key)
+			break
+		}
+		if i > 0 {
+			segments = append(segments, key[:i])
+		}
+		if key[i] == '.' {
+			key = key[i+1:]
+			continue
+		}
+		end := strings.IndexByte(key[i:], ']')
+		if end < 0 {
+			segments = append(segments, key[i:])
+			break
+		}
+		if end > 1 {
+			segments = append(segments, key[i+1:i+end])
+		}
+		key = key[i+end+1:]
+	}
+	return segments
+}
+
+// DecodeValues decodes values into dst which must be a non-nil pointer to struct or map
+// Repeated keys are decoded into slices, and nested keys like a[b][c] or list[0].name into nested fields.
+// Empty values leave non-string fields unchanged
+func DecodeValues(values url.Values, dst any, optFns ...func(options *ValuesOptions)) error {
+	options := newValuesOptions(optFns)
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("cannot decode into %T", dst)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := &valuesNode{}
+	for _, k := range keys {
+		n := root
+		for _, seg := range splitValuesKey(k) {
+			n = n.child(seg)
+		}
+		n.values = append(n.values, values[k]...)
+	}
+	return decodeValuesNode(dv.Elem(), root, "", options)
+}
+
+func decodeValuesNode(dv reflect.Value, node *valuesNode, path string, options *ValuesOptions) error {
+	if isValuesLeafType(dv.Type()) {
+		if len(node.values) == 0 {
+			if len(node.children) > 0 {
+				return fmt.Errorf("%s: cannot decode nested keys into %v", path, dv.Type())
+			}
+			return nil
+		}
+		if err := setValueString(dv, node.values[len(node.values)-1], options); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+
+	switch dv.Kind() {
+	case reflect.Ptr:
+		if dv.IsNil() {
+			dv.Set(reflect.New(dv.Type().Elem()))
+		}
+		return decodeValuesNode(dv.Elem(), node, path, options)
+	case reflect.Interface:
+		if dv.NumMethod() != 0 {
+			return fmt.Errorf("%s: cannot decode into %v", path, dv.Type())
+		}
+		dv.Set(reflect.ValueOf(valuesNodeToAny(node)))
+		return nil
+	case reflect.Slice, reflect.Array:
+		return decodeValuesList(dv, node, path, options)
+	case reflect.Map:
+		if dv.IsNil() {
+			dv.Set(reflect.MakeMap(dv.Type()))
+		}
+		for key, child := range node.children {
+			kv := reflect.New(dv.Type().Key()).Elem()
+			if err := setValueString(kv, key, options); err != nil {
+				return fmt.Errorf("%s: key %q: %w", path, key, err)
+			}
+			ev := reflect.New(dv.Type().Elem()).Elem()
+			if old := dv.MapIndex(kv); old.IsValid() {
+				ev.Set(old)
+			}
+			if err := decodeValuesNode(ev, child, joinValuesPath(path, key), options); err != nil {
+				return err
+			}
+			dv.SetMapIndex(kv, ev)
+		}
+		return nil
+	case reflect.Struct:
+		return decodeValuesStruct(dv, node, path, options)
+	default:
+		return fmt.Errorf("%s: cannot decode into %v", path, dv.Type())
+	}
+}
+
+func decodeValuesList(dv reflect.Value, node *valuesNode, path string, options *ValuesOptions) error {
+	n := len(node.values)
+	indices := make(map[int]*valuesNode, len(node.children))
+	for key, child := range node.children {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 {
+			return fmt.Errorf("%s: invalid index %q", path, key)
+		}
+		if i > options.MaxIndex {
+			return fmt.Errorf("%s: index %d exceeds max index %d", path, i, options.MaxIndex)
+		}
+		indices[i] = child
+		if i >= n {
+			n = i + 1
+		}
+	}
+
+	if dv.Kind() == reflect.Array {
+		if n > dv.Len() {
+			return fmt.Errorf("%s: %d values exceed array length %d", path, n, dv.Len())
+		}
+	} else {
+		dv.Set(reflect.MakeSlice(dv.Type(), n, n))
+	}
+
+	for i, s := range node.values {
+		if err := decodeValuesNode(dv.Index(i), &valuesNode{values: []string{s}}, joinValuesPath(path, strconv.Itoa(i)), options); err != nil {
+			return err
+		}
+	}
+	for i, child := range indices {
+		if err := decodeValuesNode(dv.Index(i), child, joinValuesPath(path, strconv.Itoa(i)), options); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeValuesStruct(dv reflect.Value, node *valuesNode, path string, options *ValuesOptions) error {
+	for _, f := range structFields(dv.Type(), options.TagName, isValuesLeafType) {
+		for key, child := range node.children {
+			if f.tagged && key != f.name {
+				continue
+			}
+			if !f.tagged && !options.FieldNameMatcher.MatchFieldName(key, f.name) {
+				continue
+			}
+			fv, err := fieldByIndexAlloc(dv, f.index)
+			if err != nil {
+				// nil embedded pointer to an unexported struct
+				break
+			}
+			if err = decodeValuesNode(fv, child, joinValuesPath(path, key), options); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func valuesNodeToAny(node *valuesNode) any {
+	if len(node.children) == 0 {
+		if len(node.values) == 1 {
+			return node.values[0]
+		}
+		return node.values
+	}
+	m := make(map[string]any, len(node.children))
+	for k, c := range node.children {
+		m[k] = valuesNodeToAny(c)
+	}
+	return m
+}
+
+func joinValuesPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "[" + key + "]"
+}
+
+// isValuesLeafType reports whether t is decoded from a single string
+func isValuesLeafType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType || t == durationType || reflect.PtrTo(t).Implements(textUnmarshaler) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+var textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func setValueString(dv reflect.Value, s string, options *ValuesOptions) error {
+	if dv.Kind() == reflect.Ptr {
+		if dv.IsNil() {
+			dv.Set(reflect.New(dv.Type().Elem()))
+		}
+		return setValueString(dv.Elem(), s, options)
+	}
+
+	if s == "" && dv.Kind() != reflect.String {
+		return nil
+	}
+
+	switch dv.Type() {
+	case timeType:
+		t, err := time.Parse(options.TimeLayout, s)
+		if err != nil {
+			sec, secErr := strconv.ParseInt(s, 10, 64)
+			if secErr != nil {
+				return err
+			}
+			t = time.Unix(sec, 0)
+		}
+		dv.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		dv.SetInt(int64(d))
+		return nil
+	}
+
+	if u, ok := dv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch {
+	case dv.Kind() == reflect.String:
+		dv.SetString(s)
+	case dv.Kind() == reflect.Bool:
+		// checkbox inputs are submitted as "on" by browsers
+		if s == "on" || s == "off" {
+			dv.SetBool(s == "on")
+			return nil
+		}
+		b, err := ToBool(s)
+		if err != nil {
+			return fmt.Errorf("parse bool: %w", err)
+		}
+		dv.SetBool(b)
+	case IsIntValue(dv):
+		i, err := ToInt64(s)
+		if err != nil {
+			return fmt.Errorf("parse int64: %w", err)
+		}
+		if dv.OverflowInt(i) {
+			return strconv.ErrRange
+		}
+		dv.SetInt(i)
+	case IsUintValue(dv):
+		i, err := ToUint64(s)
+		if err != nil {
+			return fmt.Errorf("parse uint64: %w", err)
+		}
+		if dv.OverflowUint(i) {
+			return strconv.ErrRange
+		}
+		dv.SetUint(i)
+	case IsFloatValue(dv):
+		f, err := ToFloat64(s)
+		if err != nil {
+			return fmt.Errorf("parse float64: %w", err)
+		}
+		dv.SetFloat(f)
+	case dv.Kind() == reflect.Slice && dv.Type().Elem().Kind() == reflect.Uint8:
+		dv.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("cannot decode string into %v", dv.Type())
+	}
+	return nil
+}
+
+// EncodeValues encodes src which is a struct, map or pointer to them into url.Values
+// Slices are encoded as repeated keys, nested structs and maps as bracketed keys like a[b][c].
+// Fields tagged with omitempty are omitted if they are zero values
+func EncodeValues(src any, optFns ...func(options *ValuesOptions)) (url.Values, error) {
+	options := newValuesOptions(optFns)
+	sv := reflect.ValueOf(src)
+	for sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		sv = sv.Elem()
+	}
+	if sv.Kind() != reflect.Struct && sv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("cannot encode %T into url.Values", src)
+	}
+
+	values := url.Values{}
+	if err := encodeValues(values, "", sv, options); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func encodeValues(values url.Values, key string, v reflect.Value, options *ValuesOptions) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if key != "" && isValuesLeafType(v.Type()) {
+		s, err := formatValueString(v, options)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		values.Add(key, s)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return nil
+		}
+		elemType := v.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		for i := 0; i < v.Len(); i++ {
+			k := key
+			if !isValuesLeafType(elemType) {
+				k = joinValuesPath(key, strconv.Itoa(i))
+			}
+			if err := encodeValues(values, k, v.Index(i), options); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			k, err := ToString(iter.Key().Interface())
+			if err != nil {
+				return fmt.Errorf("%s: key: %w", key, err)
+			}
+			if err = encodeValues(values, joinValuesPath(key, k), iter.Value(), options); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		for _, f := range structFields(v.Type(), options.TagName, isValuesLeafType) {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// nil embedded pointer
+				continue
+			}
+			if hasTagOption(f.opts, "omitempty") && fv.IsZero() {
+				continue
+			}
+			if err = encodeValues(values, joinValuesPath(key, f.name), fv, options); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: cannot encode %v", key, v.Type())
+	}
+}
+
+func formatValueString(v reflect.Value, options *ValuesOptions) (string, error) {
+	switch v.Type() {
+	case timeType:
+		return v.Interface().(time.Time).Format(options.TimeLayout), nil
+	case durationType:
+		return time.Duration(v.Int()).String(), nil
+	}
+
+	if v.Type().Implements(textMarshaler) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshaler) {
+		b, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch {
+	case IsFloatValue(v):
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case v.Kind() == reflect.Slice:
+		return string(v.Bytes()), nil
+	case v.Kind() == reflect.String:
+		return v.String(), nil
+	}
+	if !v.CanInterface() {
+		return "", errors.New("unexported value")
+	}
+	return ToString(v.Interface())
+}
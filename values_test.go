@@ -0,0 +1,177 @@
+package conv
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type valuesTestAddress struct {
+	City string `form:"city"`
+	Zip  string
+}
+
+type valuesTestPaging struct {
+	Page int `form:"page"`
+	Size int `form:"size,omitempty"`
+}
+
+type valuesTestQuery struct {
+	valuesTestPaging
+	UserID    int64 `form:"uid"`
+	FullName  string
+	Tags      []string            `form:"tags"`
+	Scores    []float64           `form:"scores"`
+	Active    bool                `form:"active"`
+	Since     time.Time           `form:"since"`
+	Timeout   time.Duration       `form:"timeout"`
+	IP        net.IP              `form:"ip"`
+	Limit     *int                `form:"limit"`
+	Address   valuesTestAddress   `form:"address"`
+	Contacts  []valuesTestAddress `form:"contacts"`
+	Labels    map[string]string   `form:"labels"`
+	Secret    string              `form:"-"`
+	Extra     any                 `form:"extra,omitempty"`
+	unexposed string
+}
+
+func TestDecodeValues(t *testing.T) {
+	t.Run("Good", func(t *testing.T) {
+		values, err := url.ParseQuery("page=2&uid=9007199254740993&full_name=Tom&tags=a&tags[]=b" +
+			"&scores=1.5&active=on&since=2024-01-02T03:04:05Z&timeout=1m30s&ip=10.0.0.1&limit=5" +
+			"&address[city]=Paris&address.zip=75001&contacts[1][city]=Rome&contacts[0].city=Oslo" +
+			"&labels[env]=prod&Secret=x&extra[a]=1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var q valuesTestQuery
+		if err = DecodeValues(values, &q); err != nil {
+			t.Fatal(err)
+		}
+		if q.Page != 2 || q.UserID != 9007199254740993 || q.FullName != "Tom" || !q.Active {
+			t.Fatalf("%#v", q)
+		}
+		if diff := diffSlice([]string{"a", "b"}, q.Tags); diff != "" {
+			t.Fatal(diff)
+		}
+		if diff := diffSlice([]float64{1.5}, q.Scores); diff != "" {
+			t.Fatal(diff)
+		}
+		if !q.Since.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || q.Timeout != 90*time.Second {
+			t.Fatal(q.Since, q.Timeout)
+		}
+		if q.IP.String() != "10.0.0.1" || q.Limit == nil || *q.Limit != 5 {
+			t.Fatal(q.IP, q.Limit)
+		}
+		if q.Address.City != "Paris" || q.Address.Zip != "75001" {
+			t.Fatal(q.Address)
+		}
+		if len(q.Contacts) != 2 || q.Contacts[0].City != "Oslo" || q.Contacts[1].City != "Rome" {
+			t.Fatal(q.Contacts)
+		}
+		if q.Labels["env"] != "prod" || q.Secret != "" {
+			t.Fatal(q.Labels, q.Secret)
+		}
+		if m, ok := q.Extra.(map[string]any); !ok || m["a"] != "1" {
+			t.Fatal(q.Extra)
+		}
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		var m map[string][]int
+		if err := DecodeValues(url.Values{"a": {"1", "2"}, "b": {"3"}}, &m); err != nil {
+			t.Fatal(err)
+		}
+		if diff := diffSlice([]int{1, 2}, m["a"]); diff != "" {
+			t.Fatal(diff)
+		}
+		if diff := diffSlice([]int{3}, m["b"]); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		cases := []struct {
+			Query string
+			Path  string
+		}{
+			{"uid=abc", "uid"},
+			{"page=99999999999999999999", "page"},
+			{"timeout=5", "timeout"},
+			{"contacts[x][city]=a", "contacts"},
+			{"address[city][x]=a", "address[city]"},
+			{"ip=1.2.3", "ip"},
+			{"contacts[9999999999999].city=a", "contacts"},
+			{"contacts[1001].city=a", "contacts"},
+			{"contacts[-1].city=a", "contacts"},
+		}
+		for _, c := range cases {
+			values, _ := url.ParseQuery(c.Query)
+			var q valuesTestQuery
+			err := DecodeValues(values, &q)
+			if err == nil || !strings.HasPrefix(err.Error(), c.Path+":") {
+				t.Errorf("%s: %v", c.Query, err)
+			}
+		}
+
+		var q valuesTestQuery
+		if err := DecodeValues(url.Values{}, q); err == nil {
+			t.Fatal("should fail")
+		}
+
+		values := url.Values{"contacts[5].city": {"a"}}
+		if err := DecodeValues(values, &q, func(options *ValuesOptions) {
+			options.MaxIndex = 4
+		}); err == nil || !strings.Contains(err.Error(), "exceeds max index 4") {
+			t.Fatal(err)
+		}
+		if err := DecodeValues(values, &q); err != nil || len(q.Contacts) != 6 {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestEncodeValues(t *testing.T) {
+	limit := 5
+	q := &valuesTestQuery{
+		valuesTestPaging: valuesTestPaging{Page: 2},
+		UserID:           9007199254740993,
+		FullName:         "Tom",
+		Tags:             []string{"a", "b"},
+		Scores:           []float64{1.5},
+		Since:            time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Timeout:          90 * time.Second,
+		IP:               net.ParseIP("10.0.0.1"),
+		Limit:            &limit,
+		Address:          valuesTestAddress{City: "Paris"},
+		Contacts:         []valuesTestAddress{{City: "Oslo"}},
+		Labels:           map[string]string{"env": "prod"},
+		Secret:           "x",
+	}
+
+	values, err := EncodeValues(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "FullName=Tom&active=false&address%5BZip%5D=&address%5Bcity%5D=Paris" +
+		"&contacts%5B0%5D%5BZip%5D=&contacts%5B0%5D%5Bcity%5D=Oslo&ip=10.0.0.1&labels%5Benv%5D=prod&limit=5" +
+		"&page=2&scores=1.5&since=2024-01-02T03%3A04%3A05Z&tags=a&tags=b&timeout=1m30s&uid=9007199254740993"
+	if s := values.Encode(); s != expected {
+		t.Fatalf("expect %s, got %s", expected, s)
+	}
+
+	var q2 valuesTestQuery
+	if err = DecodeValues(values, &q2); err != nil {
+		t.Fatal(err)
+	}
+	if q2.UserID != q.UserID || q2.Contacts[0].City != "Oslo" || q2.Timeout != q.Timeout || *q2.Limit != 5 {
+		t.Fatalf("%#v", q2)
+	}
+
+	if _, err = EncodeValues([]int{1}); err == nil {
+		t.Fatal("should fail")
+	}
+}
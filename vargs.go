@@ -3,8 +3,33 @@ package conv
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"reflect"
 )
 
+// VarargsOptions controls how key/value varargs are parsed
+type VarargsOptions struct {
+	// AllowNil accepts nil values, otherwise nil values are rejected
+	AllowNil bool
+
+	// Duplicates decides which value is kept if a key appears more than once, MergeOverwrite by default
+	Duplicates MergeStrategy
+}
+
+// AllowNil accepts nil values in key/value varargs
+func AllowNil() func(options *VarargsOptions) {
+	return func(options *VarargsOptions) {
+		options.AllowNil = true
+	}
+}
+
+// DuplicateStrategy sets which value is kept if a key appears more than once in key/value varargs
+func DuplicateStrategy(strategy MergeStrategy) func(options *VarargsOptions) {
+	return func(options *VarargsOptions) {
+		options.Duplicates = strategy
+	}
+}
+
 func FromVarargs(keyValues ...any) (keys []string, values []any, err error) {
 	n := len(keyValues)
 	if n%2 != 0 {
@@ -15,7 +40,7 @@ func FromVarargs(keyValues ...any) (keys []string, values []any, err error) {
 	keys, values = make([]string, 0, n/2), make([]any, 0, n/2)
 	for i := 0; i < n/2; i++ {
 		if k, ok := keyValues[2*i].(string); !ok {
-			err = fmt.Errorf("keyValues[%d] isn't convertible to string", i)
+			err = fmt.Errorf("keyValues[%d] isn't convertible to string", 2*i)
 			return
 		} else if keyValues[2*i+1] == nil {
 			err = fmt.Errorf("keyValues[%d] is nil", 2*i+1)
@@ -27,3 +52,121 @@ func FromVarargs(keyValues ...any) (keys []string, values []any, err error) {
 	}
 	return
 }
+
+// VarargsToMap converts key/value pairs to map, slog.Attr items are accepted in place of pairs
+func VarargsToMap(keyValues []any, optFns ...func(options *VarargsOptions)) (map[string]any, error) {
+	pairs, err := parseVarargs(keyValues, optFns)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, len(pairs))
+	for _, p := range pairs {
+		m[p.First] = varargValue(p.Second)
+	}
+	return m, nil
+}
+
+// VarargsToAttrs converts key/value pairs to slog attributes in order
+// Like slog, slog.Attr items can be mixed in with pairs
+func VarargsToAttrs(keyValues []any, optFns ...func(options *VarargsOptions)) ([]slog.Attr, error) {
+	pairs, err := parseVarargs(keyValues, optFns)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([]slog.Attr, len(pairs))
+	for i, p := range pairs {
+		attrs[i] = slog.Any(p.First, p.Second)
+	}
+	return attrs, nil
+}
+
+// VarargsInto assigns key/value pairs to fields of the struct which dst points to
+// Keys match json tags or field names ignoring case, '_' and '-', and values are converted with ToXxx converters
+func VarargsInto(dst any, keyValues []any, optFns ...func(options *VarargsOptions)) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dst must be a non-nil pointer to struct instead of %T", dst)
+	}
+	pairs, err := parseVarargs(keyValues, optFns)
+	if err != nil {
+		return err
+	}
+
+	dv = dv.Elem()
+	fields := structFields(dv.Type(), "json", isValuesLeafType)
+	for _, p := range pairs {
+		f := findTagField(fields, p.First, looseFieldNameMatcher{})
+		if f == nil {
+			return fmt.Errorf("%s: no matched field", p.First)
+		}
+		fv, err := fieldByIndexAlloc(dv, f.index)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.First, err)
+		}
+		if err = convertValue(fv, varargValue(p.Second)); err != nil {
+			return fmt.Errorf("%s: %w", p.First, err)
+		}
+	}
+	return nil
+}
+
+// MapToVarargs converts m to key/value pairs sorted by key
+func MapToVarargs[V any](m map[string]V) []any {
+	keyValues := make([]any, 0, 2*len(m))
+	for _, k := range SortedKeys(m) {
+		keyValues = append(keyValues, k, m[k])
+	}
+	return keyValues
+}
+
+// parseVarargs returns pairs of keys and values in order, values of slog.Attr items are kept as slog.Value
+func parseVarargs(keyValues []any, optFns []func(options *VarargsOptions)) ([]Pair[string, any], error) {
+	options := &VarargsOptions{}
+	for _, fn := range optFns {
+		fn(options)
+	}
+
+	pairs := make([]Pair[string, any], 0, len(keyValues)/2)
+	indices := make(map[string]int, len(keyValues)/2)
+	for i := 0; i < len(keyValues); i++ {
+		var p Pair[string, any]
+		switch k := keyValues[i].(type) {
+		case slog.Attr:
+			p = Pair[string, any]{First: k.Key, Second: k.Value}
+		case string:
+			if i+1 == len(keyValues) {
+				return nil, fmt.Errorf("keyValues[%d]: missing value of %s", i, k)
+			}
+			v := keyValues[i+1]
+			if v == nil && !options.AllowNil {
+				return nil, fmt.Errorf("keyValues[%d] is nil", i+1)
+			}
+			p = Pair[string, any]{First: k, Second: v}
+			i++
+		default:
+			return nil, fmt.Errorf("keyValues[%d] of type %T is neither string nor slog.Attr", i, k)
+		}
+
+		j, ok := indices[p.First]
+		if !ok {
+			indices[p.First] = len(pairs)
+			pairs = append(pairs, p)
+			continue
+		}
+		switch options.Duplicates {
+		case MergeKeepFirst:
+		case MergeError:
+			return nil, fmt.Errorf("%s: %w", p.First, ErrDuplicateKey)
+		default:
+			pairs[j] = p
+		}
+	}
+	return pairs, nil
+}
+
+func varargValue(v any) any {
+	if sv, ok := v.(slog.Value); ok {
+		return sv.Resolve().Any()
+	}
+	return v
+}
@@ -0,0 +1,140 @@
+package conv
+
+import (
+	"errors"
+	"log/slog"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromVarargs(t *testing.T) {
+	keys, values, err := FromVarargs("a", 1, "b", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffSlice([]string{"a", "b"}, keys); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := diffSlice([]any{1, "x"}, values); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if _, _, err = FromVarargs("a", 1, 2, 3); err == nil || !strings.Contains(err.Error(), "keyValues[2]") {
+		t.Fatal(err)
+	}
+	if _, _, err = FromVarargs("a", 1, "b", nil); err == nil || !strings.Contains(err.Error(), "keyValues[3]") {
+		t.Fatal(err)
+	}
+}
+
+func TestVarargsToMap(t *testing.T) {
+	t.Run("Good", func(t *testing.T) {
+		m, err := VarargsToMap([]any{"a", 1, slog.String("b", "x"), "a", 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, map[string]any{"a": 2, "b": "x"}) {
+			t.Fatal(m)
+		}
+
+		m, err = VarargsToMap([]any{"a", 1, "a", 2, "c", nil}, DuplicateStrategy(MergeKeepFirst), AllowNil())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, map[string]any{"a": 1, "c": nil}) {
+			t.Fatal(m)
+		}
+	})
+
+	t.Run("Bad", func(t *testing.T) {
+		cases := [][]any{
+			{"a"},
+			{"a", nil},
+			{1, "a"},
+		}
+		for _, c := range cases {
+			if _, err := VarargsToMap(c); err == nil {
+				t.Errorf("%v: should fail", c)
+			}
+		}
+		_, err := VarargsToMap([]any{"a", 1, "a", 2}, DuplicateStrategy(MergeError))
+		if !errors.Is(err, ErrDuplicateKey) {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestVarargsToAttrs(t *testing.T) {
+	attrs, err := VarargsToAttrs([]any{"a", 1, slog.Group("g", "b", true), "c", time.Second, "a", 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attrs) != 3 {
+		t.Fatal(attrs)
+	}
+	if !attrs[0].Equal(slog.Int("a", 3)) || !attrs[1].Equal(slog.Group("g", "b", true)) || !attrs[2].Equal(slog.Duration("c", time.Second)) {
+		t.Fatal(attrs)
+	}
+}
+
+func TestVarargsInto(t *testing.T) {
+	type Base struct {
+		ID int64 `json:"id"`
+	}
+
+	type Request struct {
+		*Base
+		UserName string
+		Timeout  time.Duration
+		Tags     []string
+		Note     *string `json:"note"`
+	}
+
+	var r Request
+	err := VarargsInto(&r, []any{"id", "12", "user_name", "tom", "timeout", "2s", "tags", []any{"a", "b"}, "note", "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Base == nil || r.ID != 12 || r.UserName != "tom" || r.Timeout != 2*time.Second || r.Note == nil || *r.Note != "x" {
+		t.Fatal(r)
+	}
+	if diff := diffSlice([]string{"a", "b"}, r.Tags); diff != "" {
+		t.Fatal(diff)
+	}
+
+	type Amount struct {
+		big.Float
+		Currency string
+	}
+	var amount Amount
+	if err = VarargsInto(&amount, []any{"float", "1.5", "currency", "EUR"}); err != nil {
+		t.Fatal(err)
+	}
+	if amount.String() != "1.5" || amount.Currency != "EUR" {
+		t.Fatal(amount.String(), amount.Currency)
+	}
+
+	if err = VarargsInto(&r, []any{"unknown", 1}); err == nil {
+		t.Fatal("should fail")
+	}
+	if err = VarargsInto(&r, []any{"id", "x"}); err == nil || !strings.HasPrefix(err.Error(), "id: ") {
+		t.Fatal(err)
+	}
+	if err = VarargsInto(r, []any{"id", 1}); err == nil {
+		t.Fatal("should fail")
+	}
+}
+
+func TestMapToVarargs(t *testing.T) {
+	kv := MapToVarargs(map[string]int{"b": 2, "a": 1, "c": 3})
+	if diff := diffSlice([]any{"a", 1, "b", 2, "c", 3}, kv); diff != "" {
+		t.Fatal(diff)
+	}
+	m, err := VarargsToMap(kv)
+	if err != nil || len(m) != 3 {
+		t.Fatal(m, err)
+	}
+}